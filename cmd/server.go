@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/thecasualcoder/dobby/pkg/handler"
 	"github.com/thecasualcoder/dobby/pkg/server"
 	"github.com/urfave/cli"
 	"strconv"
@@ -43,6 +44,82 @@ func serverFlags() []cli.Flag {
 			Usage:  "Sets the Initial readiness of the server (/readiness) (true|false)",
 			Value:  "true",
 		},
+		cli.StringFlag{
+			Name:   "initial-startup",
+			EnvVar: "INITIAL_STARTUP",
+			Usage:  "Sets the Initial startup state of the server (/startup) (true|false)",
+			Value:  "true",
+		},
+		cli.Int64Flag{
+			Name:   "startup-duration",
+			EnvVar: "STARTUP_DURATION",
+			Usage:  "Sets the time taken to boot (in seconds), /startup fails till then while the server is listening",
+			Value:  0,
+		},
+		cli.Float64Flag{
+			Name:   "health-failure-rate",
+			EnvVar: "HEALTH_FAILURE_RATE",
+			Usage:  "Sets the probability with which each /health request fails (0 to 1)",
+			Value:  0,
+		},
+		cli.Float64Flag{
+			Name:   "readiness-failure-rate",
+			EnvVar: "READINESS_FAILURE_RATE",
+			Usage:  "Sets the probability with which each /readiness request fails (0 to 1)",
+			Value:  0,
+		},
+		cli.Int64Flag{
+			Name:   "failure-seed",
+			EnvVar: "FAILURE_SEED",
+			Usage:  "Sets the seed for probabilistic failures to make them reproducible (0 picks a random seed)",
+			Value:  0,
+		},
+		cli.StringFlag{
+			Name:   "health-format",
+			EnvVar: "HEALTH_FORMAT",
+			Usage:  "Sets the rendering of /health and /readiness (json|health+json)",
+			Value:  handler.JSONFormat,
+		},
+		cli.IntFlag{
+			Name:   "history-size",
+			EnvVar: "HISTORY_SIZE",
+			Usage:  "Sets the number of state transitions kept in /control/history",
+			Value:  100,
+		},
+		cli.Int64Flag{
+			Name:   "drain-period",
+			EnvVar: "DRAIN_PERIOD",
+			Usage:  "Sets the time for which the server stays unready on SIGTERM before shutting down (in seconds)",
+			Value:  0,
+		},
+		cli.Int64Flag{
+			Name:   "shutdown-timeout",
+			EnvVar: "SHUTDOWN_TIMEOUT",
+			Usage:  "Sets the maximum time to wait for in-flight requests while shutting down (in seconds)",
+			Value:  10,
+		},
+		cli.BoolFlag{
+			Name:   "ignore-sigterm",
+			EnvVar: "IGNORE_SIGTERM",
+			Usage:  "Makes the server ignore SIGTERM, it still shuts down on SIGINT",
+		},
+		cli.Int64Flag{
+			Name:   "exit-delay",
+			EnvVar: "EXIT_DELAY",
+			Usage:  "Sets the time for which the process stays alive after shutting down on a signal (in seconds)",
+			Value:  0,
+		},
+		cli.IntFlag{
+			Name:   "exit-code",
+			EnvVar: "EXIT_CODE",
+			Usage:  "Sets the code with which the process exits after shutting down on a signal",
+			Value:  0,
+		},
+		cli.StringFlag{
+			Name:   "admin-port",
+			EnvVar: "ADMIN_PORT",
+			Usage:  "Sets the port of the admin listener serving the control routes, which keep responding while the server hangs",
+		},
 		cli.Int64Flag{
 			Name:   "initial-delay",
 			EnvVar: "INITIAL_DELAY",
@@ -67,6 +144,36 @@ func runServer(context *cli.Context) {
 		initialReadiness = readiness
 	}
 
-	err := server.Run(bindAddress, port, initialHealth, initialReadiness)
+	initialStartup := true
+	if startup, err := strconv.ParseBool(context.String("initial-startup")); err == nil {
+		initialStartup = startup
+	}
+
+	healthFailureRate := context.Float64("health-failure-rate")
+	dieIf(handler.ValidateFailureRate("health-failure-rate", healthFailureRate))
+	readinessFailureRate := context.Float64("readiness-failure-rate")
+	dieIf(handler.ValidateFailureRate("readiness-failure-rate", readinessFailureRate))
+	healthFormat := context.String("health-format")
+	dieIf(handler.ValidateHealthFormat(healthFormat))
+
+	err := server.Run(server.Config{
+		BindAddress:          bindAddress,
+		Port:                 port,
+		InitialHealth:        initialHealth,
+		InitialReadiness:     initialReadiness,
+		InitialStartup:       initialStartup,
+		StartupDuration:      time.Duration(context.Int64("startup-duration")) * time.Second,
+		HealthFailureRate:    healthFailureRate,
+		ReadinessFailureRate: readinessFailureRate,
+		FailureSeed:          context.Int64("failure-seed"),
+		HealthFormat:         healthFormat,
+		HistorySize:          context.Int("history-size"),
+		DrainPeriod:          time.Duration(context.Int64("drain-period")) * time.Second,
+		ShutdownTimeout:      time.Duration(context.Int64("shutdown-timeout")) * time.Second,
+		IgnoreSIGTERM:        context.Bool("ignore-sigterm"),
+		ExitDelay:            time.Duration(context.Int64("exit-delay")) * time.Second,
+		ExitCode:             context.Int("exit-code"),
+		AdminPort:            context.String("admin-port"),
+	})
 	dieIf(err)
 }
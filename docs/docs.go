@@ -49,22 +49,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/control/crash": {
-            "put": {
-                "description": "Make Dobby kill itself",
+        "/connection/{fault}": {
+            "get": {
+                "description": "Ask Dobby to break the connection instead of responding\nreset sends a TCP RST, close-after-headers and close-after-bytes close it before the promised body is written,\nwrong-content-length writes a longer body than promised, and malformed-status-line writes an invalid status line",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Control"
+                    "Status"
                 ],
-                "summary": "Suicide",
-                "responses": {}
+                "summary": "Connection Fault",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fault - E.g. reset",
+                        "name": "fault",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bytes of the body written before closing the connection, at most 1048576 - E.g. 100",
+                        "name": "afterBytes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
             }
         },
-        "/control/goturbo/cpu": {
-            "put": {
-                "description": "Make Dobby create a CPU spike",
+        "/control/checks": {
+            "get": {
+                "description": "Get the named checks set through the control api",
                 "consumes": [
                     "application/json"
                 ],
@@ -74,20 +99,23 @@ const docTemplate = `{
                 "tags": [
                     "Control"
                 ],
-                "summary": "CPU Spike",
+                "summary": "List Checks",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ControlSuccess"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.Check"
+                            }
                         }
                     }
                 }
             }
         },
-        "/control/goturbo/memory": {
+        "/control/checks/{name}": {
             "put": {
-                "description": "Make Dobby create a memory spike",
+                "description": "Set a named check reported in the application/health+json rendering of the probes\nA check which fails makes the probes reporting it fail, a check which warns still returns 200",
                 "consumes": [
                     "application/json"
                 ],
@@ -97,20 +125,42 @@ const docTemplate = `{
                 "tags": [
                     "Control"
                 ],
-                "summary": "Memory Spike",
+                "summary": "Set Check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the check - E.g. postgres:responseTime",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "'{status: warn, componentType: datastore, observedValue: 250, observedUnit: ms, probes: [readiness]}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Check"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/model.ControlSuccess"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
-            }
-        },
-        "/control/health/perfect": {
-            "put": {
-                "description": "Make Dobby healthy",
+            },
+            "delete": {
+                "description": "Delete a named check",
                 "consumes": [
                     "application/json"
                 ],
@@ -120,20 +170,35 @@ const docTemplate = `{
                 "tags": [
                     "Control"
                 ],
-                "summary": "Make Healthy",
+                "summary": "Delete Check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the check - E.g. postgres:responseTime",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/model.ControlSuccess"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
             }
         },
-        "/control/health/sick": {
+        "/control/crash": {
             "put": {
-                "description": "Make Dobby sick or unhealthy",
+                "description": "Make Dobby kill itself in the given mode\nfatal logs and exits with 1, panic panics in a goroutine, exit exits with exitCode,\nnil dereferences a nil pointer, concurrent-map-write causes a fatal runtime error,\nsigkill, sigsegv and sigabrt send the signal to itself",
                 "consumes": [
                     "application/json"
                 ],
@@ -143,12 +208,24 @@ const docTemplate = `{
                 "tags": [
                     "Control"
                 ],
-                "summary": "Make Unhealthy",
+                "summary": "Suicide",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Mode of the crash, defaults to fatal - E.g. panic",
+                        "name": "mode",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Recover health after sometime (seconds) - E.g. 2",
-                        "name": "resetInSeconds",
+                        "description": "Exit code for the exit mode, defaults to 1 - E.g. 137",
+                        "name": "exitCode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Crash after sometime (seconds) - E.g. 5",
+                        "name": "delayInSeconds",
                         "in": "query"
                     }
                 ],
@@ -158,13 +235,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.ControlSuccess"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
                     }
                 }
             }
         },
-        "/control/ready/perfect": {
-            "put": {
-                "description": "Make Dobby ready",
+        "/control/dependencies": {
+            "get": {
+                "description": "Get the latest check of every registered dependency",
                 "consumes": [
                     "application/json"
                 ],
@@ -174,20 +257,21 @@ const docTemplate = `{
                 "tags": [
                     "Control"
                 ],
-                "summary": "Make Ready",
+                "summary": "List Dependencies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.ControlSuccess"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.DependencyCheck"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/control/ready/sick": {
-            "put": {
-                "description": "Make Dobby unready",
+            },
+            "post": {
+                "description": "Register a downstream http url or tcp address which is checked periodically\nThe probes listed in the dependency fail whenever it is unhealthy",
                 "consumes": [
                     "application/json"
                 ],
@@ -197,28 +281,37 @@ const docTemplate = `{
                 "tags": [
                     "Control"
                 ],
-                "summary": "Make Unready",
+                "summary": "Add Dependency",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Recover readiness after sometime (seconds) - E.g. 2",
-                        "name": "resetInSeconds",
-                        "in": "query"
+                        "description": "'{name: postgres, address: postgres:5432, probes: [readiness]}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Dependency"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.ControlSuccess"
+                            "$ref": "#/definitions/model.DependencyCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
                         }
                     }
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Get Dobby's health status",
+        "/control/dependencies/{name}": {
+            "delete": {
+                "description": "Stop checking a registered dependency",
                 "consumes": [
                     "application/json"
                 ],
@@ -226,28 +319,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Status"
+                    "Control"
+                ],
+                "summary": "Delete Dependency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the dependency - E.g. postgres",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Dobby Health",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Health"
+                            "$ref": "#/definitions/model.ControlSuccess"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/model.Health"
+                            "$ref": "#/definitions/model.Error"
                         }
                     }
                 }
             }
         },
-        "/meta": {
+        "/control/disruptions": {
             "get": {
-                "description": "Get Dobby's metadata",
+                "description": "Get the disruptions started since Dobby started, or since they were last stopped\nStarting a disruption other than a rule replaces the one of its kind",
                 "consumes": [
                     "application/json"
                 ],
@@ -255,34 +357,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Status"
+                    "Control"
                 ],
-                "summary": "Dobby Metadata",
+                "summary": "List Disruptions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Metadata"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/model.Error"
-                        }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/model.Error"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Disruption"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/proxy": {
-            "post": {
-                "description": "Configure proxy route to another endpoint\nSupports all REST operations",
+            },
+            "delete": {
+                "description": "Stop every disruption and release whatever they hold",
                 "consumes": [
                     "application/json"
                 ],
@@ -290,31 +381,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Feature"
-                ],
-                "summary": "Add proxy to any http endpoint",
-                "parameters": [
-                    {
-                        "description": "'{path:/time, method: GET, proxy: {url:http://worldtimeapi.org/api/timezone/asia/kolkata, method:GET}}'",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.proxyRequest"
-                        }
-                    }
+                    "Control"
                 ],
+                "summary": "Stop Disruptions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/model.ControlSuccess"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/control/disruptions/{id}": {
             "delete": {
-                "description": "Delete configured proxy route to another endpoint\nSupports all REST operations",
+                "description": "Stop a disruption and release whatever it holds, a disruption which already ended is not running",
                 "consumes": [
                     "application/json"
                 ],
@@ -322,33 +404,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Feature"
+                    "Control"
                 ],
-                "summary": "Delete configured proxy to any http endpoint",
+                "summary": "Stop Disruption",
                 "parameters": [
                     {
-                        "description": "'{path:/time, method: GET, proxy: {url:http://worldtimeapi.org/api/timezone/asia/kolkata, method:GET}}'",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.proxyRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID of the disruption - E.g. 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object"
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
                         }
                     }
                 }
             }
         },
-        "/ready": {
+        "/control/faults": {
             "get": {
-                "description": "Get Dobby's readiness",
+                "description": "Get the rules failing the requests",
                 "consumes": [
                     "application/json"
                 ],
@@ -356,28 +448,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Status"
+                    "Control"
                 ],
-                "summary": "Dobby Ready",
+                "summary": "List Faults",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Ready"
-                        }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/model.Ready"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.FaultRule"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/return/{statusCode}": {
-            "get": {
-                "description": "Ask Dobby to return the status code sent by the client",
+            },
+            "post": {
+                "description": "Fail the percentage of the requests matching the path glob, the method and the header with the status code and body,\nor break their connection with the connection fault\nThe earliest added rule matching a request applies, the control routes never fail",
                 "consumes": [
                     "application/json"
                 ],
@@ -385,25 +472,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Status"
+                    "Control"
                 ],
-                "summary": "Repeat Status",
+                "summary": "Add Fault",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Status Code - E.g. 200",
-                        "name": "statusCode",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Dela(milliseconds) - E.g. 1000",
-                        "name": "delay",
-                        "in": "query"
+                        "description": "'{path: /proxy/*, percentage: 30, statusCode: 503, body: {error: unavailable}}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.FaultRule"
+                        }
                     }
                 ],
                 "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.FaultRule"
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -413,9 +502,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/version": {
-            "get": {
-                "description": "Get Dobby's version",
+        "/control/faults/{id}": {
+            "delete": {
+                "description": "Stop failing the requests matching a rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -423,7 +512,1953 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Status"
+                    "Control"
+                ],
+                "summary": "Delete Fault",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the rule - E.g. 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/goturbo/cpu": {
+            "get": {
+                "description": "Get the cpu load created by Dobby and the utilisation it achieved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "CPU Load",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CPULoad"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Make Dobby keep the cores busy for the utilisation percentage of every 100ms, replacing the current load\nWithout a duration it runs till it is stopped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "CPU Spike",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of cores to keep busy, defaults to 1 and at most the number of cpus - E.g. 2",
+                        "name": "cores",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Utilisation of each core, defaults to 100 - E.g. 60",
+                        "name": "utilisationPercent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop the load after sometime (seconds) - E.g. 300",
+                        "name": "durationInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CPULoad"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby stop the cpu load",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Stop CPU Load",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/goturbo/disk": {
+            "get": {
+                "description": "Get the disk filled and written by Dobby",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Disk Load",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DiskLoad"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Make Dobby fill the path with a file, and write to another file with a sync every 100ms at the given rate\nThe file of the sustained writes wraps around at 64MB. It replaces the current disk load",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Disk Spike",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Directory to write to, defaults to the temp directory - E.g. /data",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size of the file filling the path (MB) - E.g. 1024",
+                        "name": "fillInMB",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fill till the filesystem of the path is used upto the percentage - E.g. 90",
+                        "name": "fillPercent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rate of the sustained writes, at most 10000 (MB per second) - E.g. 20",
+                        "name": "writeInMBPerSecond",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DiskLoad"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby stop writing and remove the files it wrote",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Clean Disk",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/goturbo/gc": {
+            "get": {
+                "description": "Get the gc pressure created by Dobby, and the garbage collection stats of the go runtime",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "GC Stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GCPressure"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Make Dobby churn short lived objects, force garbage collection every interval, and change GOGC and the memory limit\nto cause garbage collection pauses on every other endpoint. It replaces the current gc pressure",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "GC Pressure",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rate of allocation of short lived objects (MB per second) - E.g. 200",
+                        "name": "churnInMBPerSecond",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Force garbage collection every interval (milliseconds) - E.g. 500",
+                        "name": "gcIntervalInMilliseconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "GOGC till it stops, -1 turns off the garbage collector - E.g. 20",
+                        "name": "gogc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Soft memory limit of the go runtime till it stops (MB) - E.g. 256",
+                        "name": "memoryLimitInMB",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop after sometime (seconds) - E.g. 300",
+                        "name": "durationInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GCPressure"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby stop the gc pressure, and restore GOGC and the memory limit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Stop GC Pressure",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/goturbo/memory": {
+            "get": {
+                "description": "Get the memory allocated by Dobby",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Memory Load",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.MemoryLoad"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Make Dobby allocate memory till the target and hold it, replacing the current allocation\nWithout a target it keeps allocating till it is killed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Memory Spike",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Memory to allocate (MB) - E.g. 512",
+                        "name": "targetInMB",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rate of allocation, all at once by default (MB per second) - E.g. 64",
+                        "name": "rampInMBPerSecond",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Release the memory after holding it for sometime (seconds), held till released by default - E.g. 600",
+                        "name": "holdInSeconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write to the allocated pages so that they count towards the resident set size - E.g. true",
+                        "name": "touch",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.MemoryLoad"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby release the memory it allocated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Release Memory",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/hang": {
+            "put": {
+                "description": "Make Dobby block every request, and optionally stop accepting connections, while the process stays alive\nOnly the admin listener keeps responding, durationInSeconds is required without it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Hang",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Resume after sometime (seconds), hangs till resumed by default - E.g. 60",
+                        "name": "durationInSeconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Stop accepting connections as well - E.g. true",
+                        "name": "refuseConnections",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby respond again after it hangs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Resume",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/health/flaky": {
+            "put": {
+                "description": "Make every request to Dobby's health fail with the given probability",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Health Flaky",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Probability of a health request to fail, 0 disables it - E.g. 0.3",
+                        "name": "failureRate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seed to make the failures reproducible - E.g. 42",
+                        "name": "seed",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/health/flap": {
+            "put": {
+                "description": "Make Dobby alternate between healthy and unhealthy\nDobby is healthy for the first dutyCycle fraction of every period",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Health Flap",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Length of one healthy and unhealthy cycle (seconds) - E.g. 10",
+                        "name": "periodInSeconds",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Fraction of the period to be healthy, defaults to 0.5 - E.g. 0.8",
+                        "name": "dutyCycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop flapping after sometime (seconds) - E.g. 60",
+                        "name": "durationInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/health/perfect": {
+            "put": {
+                "description": "Make Dobby healthy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Healthy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/health/sick": {
+            "put": {
+                "description": "Make Dobby sick or unhealthy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Unhealthy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recover health after sometime (seconds) - E.g. 2",
+                        "name": "resetInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/health/slow": {
+            "put": {
+                "description": "Make Dobby respond slowly to health requests\nDelays by a fixed delay, a random delay between minDelay and maxDelay, or hangs till the client disconnects\nCalling without any of them makes health respond promptly again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Health Slow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fixed delay (milliseconds) - E.g. 1000",
+                        "name": "delay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum delay (milliseconds) - E.g. 500",
+                        "name": "minDelay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum delay (milliseconds) - E.g. 3000",
+                        "name": "maxDelay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hang till the client disconnects - E.g. true",
+                        "name": "hang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/history": {
+            "get": {
+                "description": "Get the latest state transitions of Dobby, from the oldest to the latest\nIncludes changes to health, readiness and startup, whether caused by the control api, flapping, dependencies or checks,\nchanges to the modes of the probes, proxies being added or deleted and disruptions being started",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "State History",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Transition"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/control/latency": {
+            "get": {
+                "description": "Get the rules delaying the requests",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "List Latency",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.LatencyRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Delay the requests matching the path glob and the method, by a delay from the distribution\nThe earliest added rule matching a request applies, the control routes are never delayed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Add Latency",
+                "parameters": [
+                    {
+                        "description": "'{path: /call, method: POST, distribution: normal, delayInMilliseconds: 200, stdDevInMilliseconds: 50}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LatencyRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.LatencyRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/latency/{id}": {
+            "delete": {
+                "description": "Stop delaying the requests matching a rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Delete Latency",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the rule - E.g. 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/leak/memory": {
+            "get": {
+                "description": "Get the progress of the memory leak of Dobby",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Leaked Memory",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.MemoryLeak"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Make Dobby leak memory at the given rate till the ceiling, replacing the current memory leak\nWithout a ceiling it keeps leaking till it is killed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Leak Memory",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Memory leaked every minute, defaults to 10 and at most 60000 (MB) - E.g. 5",
+                        "name": "rateInMBPerMinute",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Memory at which the leak stops growing (MB) - E.g. 1024",
+                        "name": "ceilingInMB",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write to the leaked pages so that they count towards the resident set size - E.g. true",
+                        "name": "touch",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.MemoryLeak"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby free the memory it leaked, like stopping the memory leak disruption",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Free Memory Leak",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/leak/{kind}": {
+            "get": {
+                "description": "Get the number of goroutines, files or sockets leaked by Dobby",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Leaked",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kind of the leak - E.g. sockets",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Leak"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Make Dobby leak goroutines, files or sockets at the given rate till the max, replacing the current leak of the kind\ngoroutines are blocked forever, files are opened and sockets are connected to itself (two file descriptors each)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Leak",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kind of the leak - E.g. sockets",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number leaked every second, defaults to 10 and at most 1000 - E.g. 5",
+                        "name": "ratePerSecond",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum leaked, defaults to 1000 - E.g. 500",
+                        "name": "max",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Leak"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Make Dobby close the goroutines, files or sockets it leaked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Free Leak",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kind of the leak - E.g. sockets",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/outbound": {
+            "get": {
+                "description": "Get the rules degrading the requests Dobby makes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "List Outbound Faults",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OutboundRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Delay, fail the percentage of, or blackhole the requests Dobby makes to the hosts matching the glob,\nwhen calling, proxying and checking its dependencies. Failures are connection errors, or synthetic responses with the status code\nThe earliest added rule matching a request applies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Add Outbound Fault",
+                "parameters": [
+                    {
+                        "description": "'{host: httpbin.org, delayInMilliseconds: 200, percentage: 30, statusCode: 503}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OutboundRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.OutboundRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/outbound/{id}": {
+            "delete": {
+                "description": "Stop degrading the requests Dobby makes to the hosts matching a rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Delete Outbound Fault",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the rule - E.g. 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/ready/flaky": {
+            "put": {
+                "description": "Make every request to Dobby's readiness fail with the given probability",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Readiness Flaky",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Probability of a readiness request to fail, 0 disables it - E.g. 0.3",
+                        "name": "failureRate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seed to make the failures reproducible - E.g. 42",
+                        "name": "seed",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/ready/flap": {
+            "put": {
+                "description": "Make Dobby alternate between ready and unready\nDobby is ready for the first dutyCycle fraction of every period",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Readiness Flap",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Length of one ready and unready cycle (seconds) - E.g. 10",
+                        "name": "periodInSeconds",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Fraction of the period to be ready, defaults to 0.5 - E.g. 0.8",
+                        "name": "dutyCycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stop flapping after sometime (seconds) - E.g. 60",
+                        "name": "durationInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/ready/perfect": {
+            "put": {
+                "description": "Make Dobby ready",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Ready",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/ready/sick": {
+            "put": {
+                "description": "Make Dobby unready",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Unready",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recover readiness after sometime (seconds) - E.g. 2",
+                        "name": "resetInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/ready/slow": {
+            "put": {
+                "description": "Make Dobby respond slowly to readiness requests\nDelays by a fixed delay, a random delay between minDelay and maxDelay, or hangs till the client disconnects\nCalling without any of them makes readiness respond promptly again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Readiness Slow",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Fixed delay (milliseconds) - E.g. 1000",
+                        "name": "delay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum delay (milliseconds) - E.g. 500",
+                        "name": "minDelay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum delay (milliseconds) - E.g. 3000",
+                        "name": "maxDelay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hang till the client disconnects - E.g. true",
+                        "name": "hang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/shutdown": {
+            "put": {
+                "description": "Make Dobby shut down gracefully and exit with the given code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Shutdown",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Exit code, defaults to 0 - E.g. 3",
+                        "name": "exitCode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Shut down after sometime (seconds) - E.g. 5",
+                        "name": "delayInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/slow": {
+            "get": {
+                "description": "Get the rules slowing down the responses",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "List Slow Responses",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.SlowResponseRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Slow down the responses of the requests matching the path glob and the method\nThe earliest added rule matching a request applies, the control routes are never slowed down",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Add Slow Response",
+                "parameters": [
+                    {
+                        "description": "'{path: /proxy/*, mode: throttle, bytesPerSecond: 100}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SlowResponseRule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.SlowResponseRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/slow/{id}": {
+            "delete": {
+                "description": "Stop slowing down the responses of the requests matching a rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Delete Slow Response",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the rule - E.g. 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/startup/perfect": {
+            "put": {
+                "description": "Make Dobby finish its startup, skipping any pending boot",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Started",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/startup/sick": {
+            "put": {
+                "description": "Make Dobby fail its startup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Make Not Started",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Recover startup after sometime (seconds) - E.g. 2",
+                        "name": "resetInSeconds",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    }
+                }
+            }
+        },
+        "/control/timers": {
+            "get": {
+                "description": "Get the pending recoveries scheduled through resetInSeconds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Pending Timers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Timer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/control/timers/{id}": {
+            "delete": {
+                "description": "Cancel a pending recovery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Control"
+                ],
+                "summary": "Cancel Timer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the timer - E.g. 1",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ControlSuccess"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Get Dobby's health status\nRenders application/health+json when asked for in the Accept header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/health+json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Dobby Health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Health"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/meta": {
+            "get": {
+                "description": "Get Dobby's metadata",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Dobby Metadata",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Metadata"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/proxy": {
+            "post": {
+                "description": "Configure proxy route to another endpoint\nSupports all REST operations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feature"
+                ],
+                "summary": "Add proxy to any http endpoint",
+                "parameters": [
+                    {
+                        "description": "'{path:/time, method: GET, proxy: {url:http://worldtimeapi.org/api/timezone/asia/kolkata, method:GET}}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.proxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete configured proxy route to another endpoint\nSupports all REST operations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feature"
+                ],
+                "summary": "Delete configured proxy to any http endpoint",
+                "parameters": [
+                    {
+                        "description": "'{path:/time, method: GET, proxy: {url:http://worldtimeapi.org/api/timezone/asia/kolkata, method:GET}}'",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.proxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "Get Dobby's readiness\nRenders application/health+json when asked for in the Accept header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/health+json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Dobby Ready",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Ready"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Ready"
+                        }
+                    }
+                }
+            }
+        },
+        "/return/{statusCode}": {
+            "get": {
+                "description": "Ask Dobby to return the status code sent by the client",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Repeat Status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Status Code - E.g. 200",
+                        "name": "statusCode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Dela(milliseconds) - E.g. 1000",
+                        "name": "delay",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/slow/{mode}": {
+            "get": {
+                "description": "Ask Dobby to respond with a body of the given size slowly\nthrottle writes it at bytesPerSecond, slowloris writes the headers and then a byte of it every intervalInSeconds",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Slow Response",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Mode - E.g. throttle",
+                        "name": "mode",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Bytes written every second when throttling - E.g. 100",
+                        "name": "bytesPerSecond",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Interval between the bytes of slowloris, at most 3600 - E.g. 5",
+                        "name": "intervalInSeconds",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size of the body, defaults to 1024 and at most 10485760 - E.g. 500",
+                        "name": "sizeInBytes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/startup": {
+            "get": {
+                "description": "Get Dobby's startup status\nFails till the boot is complete, even though the server is already listening",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Dobby Startup",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Startup"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Startup"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Get Dobby's version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
                 ],
                 "summary": "Dobby Version",
                 "responses": {
@@ -461,38 +2496,237 @@ const docTemplate = `{
                 }
             }
         },
-        "handler.proxyRequest": {
+        "handler.proxyRequest": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "proxy": {
+                    "$ref": "#/definitions/handler.proxy"
+                }
+            }
+        },
+        "model.CPULoad": {
+            "type": "object",
+            "properties": {
+                "achievedPercent": {
+                    "description": "AchievedPercent is the busy time of the cores divided by the time for which they ran",
+                    "type": "number",
+                    "example": 59.8
+                },
+                "cores": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "durationInSeconds": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "numCPU": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "utilisationPercent": {
+                    "type": "integer",
+                    "example": 60
+                }
+            }
+        },
+        "model.CallRequest": {
+            "type": "object",
+            "properties": {
+                "body": {},
+                "method": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Check": {
             "type": "object",
             "properties": {
-                "method": {
-                    "type": "string"
+                "componentId": {
+                    "type": "string",
+                    "example": "postgres"
                 },
-                "path": {
-                    "type": "string"
+                "componentType": {
+                    "type": "string",
+                    "example": "datastore"
                 },
-                "proxy": {
-                    "$ref": "#/definitions/handler.proxy"
+                "observedUnit": {
+                    "type": "string",
+                    "example": "ms"
+                },
+                "observedValue": {},
+                "output": {
+                    "type": "string",
+                    "example": "replication lag is high"
+                },
+                "probes": {
+                    "description": "Probes which report the check (health|readiness), all of them when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "readiness"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "example": "warn"
+                },
+                "time": {
+                    "type": "string",
+                    "example": "2021-03-16T11:58:02Z"
                 }
             }
         },
-        "model.CallRequest": {
+        "model.ControlSuccess": {
             "type": "object",
             "properties": {
-                "body": {},
-                "method": {
-                    "type": "string"
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "model.Dependency": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Address of a tcp dependency, which is healthy when a connection can be established",
+                    "type": "string",
+                    "example": "postgres:5432"
+                },
+                "intervalInSeconds": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "name": {
+                    "type": "string",
+                    "example": "postgres"
+                },
+                "probes": {
+                    "description": "Probes which fail when the dependency is unhealthy (health|readiness)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "readiness"
+                    ]
+                },
+                "timeoutInSeconds": {
+                    "type": "integer",
+                    "example": 2
                 },
                 "url": {
+                    "description": "URL of a http dependency, which is healthy when it responds with a status code below 400",
+                    "type": "string",
+                    "example": "http://dobby2:4444/health"
+                }
+            }
+        },
+        "model.DependencyCheck": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
                     "type": "string"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "dial tcp 10.0.0.1:5432: connect: connection refused"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "postgres"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "postgres:5432"
                 }
             }
         },
-        "model.ControlSuccess": {
+        "model.DiskLoad": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error is the last error when filling or writing, e.g. when the disk is full",
+                    "type": "string",
+                    "example": "write /data/dobby-fill-1234: no space left on device"
+                },
+                "fillInMB": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "fillPercent": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "filledInMB": {
+                    "type": "integer",
+                    "example": 1024
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/data"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "holding"
+                },
+                "usedPercent": {
+                    "description": "UsedPercent is the usage of the filesystem of the path, when it is known",
+                    "type": "number",
+                    "example": 90.2
+                },
+                "writeInMBPerSecond": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "writtenInMB": {
+                    "type": "integer",
+                    "example": 600
+                }
+            }
+        },
+        "model.Disruption": {
             "type": "object",
             "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "parameters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "startedAt": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "string",
-                    "example": "success"
+                    "example": "holding"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "memory"
                 }
             }
         },
@@ -505,14 +2739,273 @@ const docTemplate = `{
                 }
             }
         },
+        "model.FaultRule": {
+            "type": "object",
+            "properties": {
+                "afterBytes": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "body": {},
+                "connectionFault": {
+                    "description": "ConnectionFault breaks the connection instead of responding with the status code,\none of reset, close-after-headers, close-after-bytes, wrong-content-length or malformed-status-line",
+                    "type": "string",
+                    "example": "reset"
+                },
+                "header": {
+                    "description": "Header is the name of a header the requests should have, with HeaderValue if it is given",
+                    "type": "string",
+                    "example": "X-Canary"
+                },
+                "headerValue": {
+                    "type": "string",
+                    "example": "true"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "path": {
+                    "description": "Path is a glob matching the path of the requests, e.g. /proxy/* - an empty one matches all of them",
+                    "type": "string",
+                    "example": "/proxy/*"
+                },
+                "percentage": {
+                    "description": "Percentage of the matching requests which fail",
+                    "type": "number",
+                    "example": 30
+                },
+                "statusCode": {
+                    "type": "integer",
+                    "example": 503
+                }
+            }
+        },
+        "model.GCPressure": {
+            "type": "object",
+            "properties": {
+                "churnInMBPerSecond": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "durationInSeconds": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "gcIntervalInMilliseconds": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "gogc": {
+                    "description": "GOGC and MemoryLimitInMB are left as they are when not given, they are restored once it stops",
+                    "type": "integer",
+                    "example": 20
+                },
+                "lastPauseInMicroseconds": {
+                    "type": "number",
+                    "example": 120.5
+                },
+                "memoryLimitInMB": {
+                    "type": "integer",
+                    "example": 256
+                },
+                "numGC": {
+                    "description": "NumGC, PauseTotalInMilliseconds and LastPauseInMicroseconds are reported by the go runtime for the whole process",
+                    "type": "integer",
+                    "example": 1042
+                },
+                "pauseTotalInMilliseconds": {
+                    "type": "number",
+                    "example": 84.2
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
         "model.Health": {
             "type": "object",
             "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DependencyCheck"
+                    }
+                },
                 "healthy": {
                     "type": "boolean"
                 }
             }
         },
+        "model.LatencyRule": {
+            "type": "object",
+            "properties": {
+                "delayInMilliseconds": {
+                    "description": "DelayInMilliseconds is the fixed delay, or the mean of the normal and exponential delays",
+                    "type": "integer",
+                    "example": 200
+                },
+                "distribution": {
+                    "description": "Distribution is one of fixed, uniform, normal or exponential",
+                    "type": "string",
+                    "example": "normal"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "maxDelayInMilliseconds": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "minDelayInMilliseconds": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "path": {
+                    "description": "Path is a glob matching the path of the requests, e.g. /proxy/* - an empty one matches all of them",
+                    "type": "string",
+                    "example": "/call"
+                },
+                "spikeDelayInMilliseconds": {
+                    "type": "integer",
+                    "example": 5000
+                },
+                "spikeRate": {
+                    "description": "SpikeRate is the probability with which SpikeDelayInMilliseconds is added to the delay",
+                    "type": "number",
+                    "example": 0.01
+                },
+                "stdDevInMilliseconds": {
+                    "type": "integer",
+                    "example": 50
+                }
+            }
+        },
+        "model.Leak": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error is the last error when leaking, e.g. when the process runs out of file descriptors",
+                    "type": "string",
+                    "example": "open /dev/null: too many open files"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "sockets"
+                },
+                "leaked": {
+                    "type": "integer",
+                    "example": 420
+                },
+                "max": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "numGoroutine": {
+                    "type": "integer",
+                    "example": 428
+                },
+                "ratePerSecond": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "status": {
+                    "type": "string",
+                    "example": "leaking"
+                }
+            }
+        },
+        "model.MemoryLeak": {
+            "type": "object",
+            "properties": {
+                "ceilingInMB": {
+                    "description": "CeilingInMB is where the leak stops growing, it grows till the process is killed when it is 0",
+                    "type": "integer",
+                    "example": 1024
+                },
+                "ceilingInSeconds": {
+                    "description": "CeilingInSeconds is the time left till the ceiling is reached",
+                    "type": "integer",
+                    "example": 5280
+                },
+                "heapInUseInMB": {
+                    "description": "HeapInUseInMB and SysInMB are reported by the go runtime for the whole process",
+                    "type": "integer",
+                    "example": 124
+                },
+                "leakedInMB": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "progressPercent": {
+                    "description": "ProgressPercent is the percentage of the ceiling leaked so far",
+                    "type": "number",
+                    "example": 11.7
+                },
+                "rateInMBPerMinute": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "status": {
+                    "type": "string",
+                    "example": "leaking"
+                },
+                "sysInMB": {
+                    "type": "integer",
+                    "example": 140
+                },
+                "touch": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.MemoryLoad": {
+            "type": "object",
+            "properties": {
+                "allocatedInMB": {
+                    "type": "integer",
+                    "example": 512
+                },
+                "heapInUseInMB": {
+                    "description": "HeapInUseInMB and SysInMB are reported by the go runtime for the whole process",
+                    "type": "integer",
+                    "example": 516
+                },
+                "holdInSeconds": {
+                    "type": "integer",
+                    "example": 600
+                },
+                "rampInMBPerSecond": {
+                    "type": "integer",
+                    "example": 64
+                },
+                "status": {
+                    "type": "string",
+                    "example": "holding"
+                },
+                "sysInMB": {
+                    "type": "integer",
+                    "example": 540
+                },
+                "targetInMB": {
+                    "type": "integer",
+                    "example": 512
+                },
+                "touch": {
+                    "type": "boolean"
+                }
+            }
+        },
         "model.Metadata": {
             "type": "object",
             "properties": {
@@ -526,14 +3019,147 @@ const docTemplate = `{
                 }
             }
         },
+        "model.OutboundRule": {
+            "type": "object",
+            "properties": {
+                "blackhole": {
+                    "description": "Blackhole makes the matching requests hang till TimeoutInSeconds, like a host dropping the packets",
+                    "type": "boolean"
+                },
+                "delayInMilliseconds": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "host": {
+                    "description": "Host is a glob matching the host of the outbound requests, e.g. *.example.com - an empty one matches all of them",
+                    "type": "string",
+                    "example": "httpbin.org"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "percentage": {
+                    "description": "Percentage of the matching requests which fail, with StatusCode when it is given or with a connection error",
+                    "type": "number",
+                    "example": 30
+                },
+                "statusCode": {
+                    "type": "integer",
+                    "example": 503
+                },
+                "timeoutInSeconds": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
         "model.Ready": {
             "type": "object",
             "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DependencyCheck"
+                    }
+                },
                 "ready": {
                     "type": "boolean"
                 }
             }
         },
+        "model.SlowResponseRule": {
+            "type": "object",
+            "properties": {
+                "bytesPerSecond": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "intervalInSeconds": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "mode": {
+                    "description": "Mode is throttle, writing the body at BytesPerSecond,\nor slowloris, writing the headers and then a byte of the body every IntervalInSeconds",
+                    "type": "string",
+                    "example": "throttle"
+                },
+                "path": {
+                    "description": "Path is a glob matching the path of the requests, e.g. /proxy/* - an empty one matches all of them",
+                    "type": "string",
+                    "example": "/proxy/*"
+                }
+            }
+        },
+        "model.Startup": {
+            "type": "object",
+            "properties": {
+                "started": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Timer": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "reset health to true"
+                },
+                "firesAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "remainingSeconds": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "health"
+                }
+            }
+        },
+        "model.Transition": {
+            "type": "object",
+            "properties": {
+                "cause": {
+                    "type": "string",
+                    "example": "PUT /control/health/sick?resetInSeconds=60"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "true"
+                },
+                "remoteAddr": {
+                    "type": "string",
+                    "example": "10.0.0.1:53422"
+                },
+                "subject": {
+                    "description": "Subject which changed (health|readiness|startup|proxy|disruption)",
+                    "type": "string",
+                    "example": "health"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "false"
+                }
+            }
+        },
         "model.Version": {
             "type": "object",
             "properties": {
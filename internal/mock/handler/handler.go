@@ -88,6 +88,20 @@ func (mr *MockContextMockRecorder) GetMethod() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMethod", reflect.TypeOf((*MockContext)(nil).GetMethod))
 }
 
+// GetRemoteAddr mocks base method.
+func (m *MockContext) GetRemoteAddr() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRemoteAddr")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetRemoteAddr indicates an expected call of GetRemoteAddr.
+func (mr *MockContextMockRecorder) GetRemoteAddr() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRemoteAddr", reflect.TypeOf((*MockContext)(nil).GetRemoteAddr))
+}
+
 // GetRequestBody mocks base method.
 func (m *MockContext) GetRequestBody() io.ReadCloser {
 	m.ctrl.T.Helper()
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	resetFault               = "reset"
+	closeAfterHeadersFault   = "close-after-headers"
+	closeAfterBytesFault     = "close-after-bytes"
+	wrongContentLengthFault  = "wrong-content-length"
+	malformedStatusLineFault = "malformed-status-line"
+
+	// connectionFaultBodySize is the size of the body promised by the faults which close the connection early
+	connectionFaultBodySize = 1024
+	// maxAfterBytes is the most bytes of the body written before breaking the connection
+	maxAfterBytes = 1024 * 1024
+	// bodyBlockSize is the size of the blocks in which the bodies are written
+	bodyBlockSize = 4096
+)
+
+var bodyBlock = []byte(strings.Repeat("x", bodyBlockSize))
+
+// connectionFaults break the hijacked connection in different ways, afterBytes is the bytes of the body written before closing it
+var connectionFaults = map[string]func(conn net.Conn, writer *bufio.Writer, afterBytes int){
+	resetFault: func(conn net.Conn, _ *bufio.Writer, _ int) {
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			_ = tcpConn.SetLinger(0)
+		}
+	},
+	closeAfterHeadersFault: func(_ net.Conn, writer *bufio.Writer, _ int) {
+		writeHeaders(writer, connectionFaultBodySize)
+	},
+	closeAfterBytesFault: func(_ net.Conn, writer *bufio.Writer, afterBytes int) {
+		writeHeaders(writer, afterBytes+connectionFaultBodySize)
+		_ = writeBody(writer, afterBytes)
+	},
+	wrongContentLengthFault: func(_ net.Conn, writer *bufio.Writer, afterBytes int) {
+		writeHeaders(writer, afterBytes)
+		_ = writeBody(writer, afterBytes+connectionFaultBodySize)
+	},
+	malformedStatusLineFault: func(_ net.Conn, writer *bufio.Writer, _ int) {
+		_, _ = writer.WriteString("HTTP/1.1 two-hundred OK-ish\r\nContent-Length: 0\r\n\r\n")
+	},
+}
+
+func writeHeaders(writer *bufio.Writer, contentLength int) {
+	_, _ = fmt.Fprintf(writer, "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: %d\r\nDate: %s\r\n\r\n",
+		contentLength, time.Now().UTC().Format(http.TimeFormat))
+}
+
+// writeBody writes the size of body in blocks, so that the whole body is never held in memory
+func writeBody(writer io.Writer, size int) error {
+	for size > 0 {
+		block := bodyBlock
+		if size < len(block) {
+			block = block[:size]
+		}
+		n, err := writer.Write(block)
+		if err != nil {
+			return err
+		}
+		size -= n
+	}
+	return nil
+}
+
+func connectionFaultNames() []string {
+	names := make([]string, 0, len(connectionFaults))
+	for name := range connectionFaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validateConnectionFault(fault string, afterBytes int) error {
+	if _, ok := connectionFaults[fault]; !ok {
+		return fmt.Errorf("connection fault should be one of %s", strings.Join(connectionFaultNames(), ", "))
+	}
+	if afterBytes < 0 || afterBytes > maxAfterBytes {
+		return fmt.Errorf("afterBytes should be between 0 and %d", maxAfterBytes)
+	}
+	return nil
+}
+
+// breakConnection hijacks the connection of the request and breaks it with the fault
+func breakConnection(c *gin.Context, fault string, afterBytes int) {
+	conn, buffer, err := c.Writer.Hijack()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, model.Error{Error: fmt.Sprintf("error when hijacking the connection: %s", err.Error())})
+		return
+	}
+	c.Abort()
+	connectionFaults[fault](conn, buffer.Writer, afterBytes)
+	_ = buffer.Flush()
+	_ = conn.Close()
+}
+
+// ConnectionFault godoc
+// @Summary Connection Fault
+// @Description Ask Dobby to break the connection instead of responding
+// @Description reset sends a TCP RST, close-after-headers and close-after-bytes close it before the promised body is written,
+// @Description wrong-content-length writes a longer body than promised, and malformed-status-line writes an invalid status line
+// @Tags Status
+// @Accept json
+// @Produce json
+// @Failure 400 {object} model.Error
+// @Param fault path string true "Fault - E.g. reset"
+// @Param afterBytes query int false "Bytes of the body written before closing the connection, at most 1048576 - E.g. 100"
+// @Router /connection/{fault} [get]
+func (h *Handler) ConnectionFault(c *gin.Context) {
+	fault := c.Param("fault")
+	afterBytes := 0
+	if afterBytesStr := c.Query("afterBytes"); afterBytesStr != "" {
+		var err error
+		if afterBytes, err = strconv.Atoi(afterBytesStr); err != nil {
+			afterBytes = -1
+		}
+	}
+	if err := validateConnectionFault(fault, afterBytes); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	breakConnection(c, fault, afterBytes)
+}
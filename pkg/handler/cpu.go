@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	cpuSlice = 100 * time.Millisecond
+
+	cpuIdle    = "idle"
+	cpuRunning = "running"
+	cpuStopped = "stopped"
+)
+
+// cpuLoad keeps the given number of cores busy for the utilisation percentage of every slice,
+// a duration of 0 keeps them busy till it is stopped
+type cpuLoad struct {
+	config    model.CPULoad
+	stop      chan struct{}
+	once      sync.Once
+	startedAt time.Time
+	busy      atomic.Int64
+
+	mutex     sync.RWMutex
+	stoppedAt time.Time
+}
+
+func newCPULoad(config model.CPULoad) *cpuLoad {
+	return &cpuLoad{config: config, stop: make(chan struct{}), startedAt: time.Now()}
+}
+
+func (l *cpuLoad) work() {
+	busy := cpuSlice * time.Duration(l.config.UtilisationPercent) / 100
+	idle := time.NewTimer(0)
+	defer idle.Stop()
+	for {
+		start := time.Now()
+		for time.Since(start) < busy {
+		}
+		l.busy.Add(int64(time.Since(start)))
+		idle.Reset(cpuSlice - busy)
+		select {
+		case <-l.stop:
+			return
+		case <-idle.C:
+		}
+	}
+}
+
+// run starts the workers, onExpiry is called if it is not stopped before the duration
+func (l *cpuLoad) run(onExpiry func()) {
+	for i := 0; i < l.config.Cores; i++ {
+		go l.work()
+	}
+	if l.config.DurationInSeconds == 0 {
+		return
+	}
+	duration := time.NewTimer(time.Duration(l.config.DurationInSeconds) * time.Second)
+	defer duration.Stop()
+	select {
+	case <-l.stop:
+	case <-duration.C:
+		onExpiry()
+	}
+}
+
+func (l *cpuLoad) release() {
+	l.once.Do(func() {
+		close(l.stop)
+		l.mutex.Lock()
+		l.stoppedAt = time.Now()
+		l.mutex.Unlock()
+	})
+}
+
+func (l *cpuLoad) currentStatus() string {
+	if l.stopped() {
+		return cpuStopped
+	}
+	return cpuRunning
+}
+
+func (l *cpuLoad) stopped() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return !l.stoppedAt.IsZero()
+}
+
+func (l *cpuLoad) report() model.CPULoad {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	report := l.config
+	report.Status = cpuRunning
+	until := time.Now()
+	if !l.stoppedAt.IsZero() {
+		report.Status = cpuStopped
+		until = l.stoppedAt
+	}
+	if elapsed := until.Sub(l.startedAt); elapsed > 0 {
+		report.AchievedPercent = 100 * float64(l.busy.Load()) / float64(elapsed*time.Duration(l.config.Cores))
+	}
+	report.NumCPU = runtime.NumCPU()
+	return report
+}
+
+func cpuLoadFromQuery(c *gin.Context) (model.CPULoad, error) {
+	config := model.CPULoad{Cores: 1, UtilisationPercent: 100}
+	if coresStr := c.Query("cores"); coresStr != "" {
+		cores, err := strconv.Atoi(coresStr)
+		if err != nil || cores <= 0 || cores > runtime.NumCPU() {
+			return config, fmt.Errorf("cores should be between 1 and %d", runtime.NumCPU())
+		}
+		config.Cores = cores
+	}
+	if utilisationStr := c.Query("utilisationPercent"); utilisationStr != "" {
+		utilisation, err := strconv.Atoi(utilisationStr)
+		if err != nil || utilisation <= 0 || utilisation > 100 {
+			return config, fmt.Errorf("utilisationPercent should be between 1 and 100")
+		}
+		config.UtilisationPercent = utilisation
+	}
+	if durationStr := c.Query("durationInSeconds"); durationStr != "" {
+		duration, err := strconv.Atoi(durationStr)
+		if err != nil || duration <= 0 {
+			return config, fmt.Errorf("durationInSeconds should be a positive integer")
+		}
+		config.DurationInSeconds = duration
+	}
+	return config, nil
+}
+
+// GoTurboCPU will make dobby go Turbo
+// Watch the video `https://youtu.be/TNjAZZ3vQ8o?t=14`
+// for more context on `Going Turbo`
+// @Summary CPU Spike
+// @Description Make Dobby keep the cores busy for the utilisation percentage of every 100ms, replacing the current load
+// @Description Without a duration it runs till it is stopped
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param cores query int false "Number of cores to keep busy, defaults to 1 and at most the number of cpus - E.g. 2"
+// @Param utilisationPercent query int false "Utilisation of each core, defaults to 100 - E.g. 60"
+// @Param durationInSeconds query int false "Stop the load after sometime (seconds) - E.g. 300"
+// @Success 200 {object} model.CPULoad
+// @Failure 400 {object} model.Error
+// @Router /control/goturbo/cpu [put]
+func (h *Handler) GoTurboCPU(c *gin.Context) {
+	config, err := cpuLoadFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	description := fmt.Sprintf("cpu load of %d%% on %d cores", config.UtilisationPercent, config.Cores)
+	h.history.record(disruptionSubject, "", description, controlCause(c), c.Request.RemoteAddr)
+	load := newCPULoad(config)
+	h.disruptions.replace(cpuDisruption, disruptionParameters(c), load)
+	go load.run(func() {
+		load.release()
+		h.history.record(disruptionSubject, "", "cpu load stopped", "duration elapsed", "")
+	})
+	c.JSON(http.StatusOK, load.report())
+}
+
+// GetCPULoad godoc
+// @Summary CPU Load
+// @Description Get the cpu load created by Dobby and the utilisation it achieved
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.CPULoad
+// @Router /control/goturbo/cpu [get]
+func (h *Handler) GetCPULoad(c *gin.Context) {
+	d, ok := h.disruptions.find(cpuDisruption)
+	if !ok {
+		c.JSON(http.StatusOK, model.CPULoad{Status: cpuIdle, NumCPU: runtime.NumCPU()})
+		return
+	}
+	c.JSON(http.StatusOK, d.(*cpuLoad).report())
+}
+
+// StopCPULoad godoc
+// @Summary Stop CPU Load
+// @Description Make Dobby stop the cpu load
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/goturbo/cpu [delete]
+func (h *Handler) StopCPULoad(c *gin.Context) {
+	if len(h.disruptions.stopKind(cpuDisruption)) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: "no cpu load is running"})
+		return
+	}
+	h.history.record(disruptionSubject, "", "cpu load stopped", controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
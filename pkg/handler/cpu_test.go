@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestCPULoad(t *testing.T) {
+	t.Run("should keep the core busy for the utilisation percentage", func(t *testing.T) {
+		load := newCPULoad(model.CPULoad{Cores: 1, UtilisationPercent: 50})
+		go load.run(func() { t.Error("load without duration should not expire") })
+
+		time.Sleep(time.Second)
+		load.release()
+
+		report := load.report()
+		assert.Equal(t, cpuStopped, report.Status)
+		assert.InDelta(t, 50, report.AchievedPercent, 20)
+	})
+
+	t.Run("should expire after the duration", func(t *testing.T) {
+		load := newCPULoad(model.CPULoad{Cores: 1, UtilisationPercent: 10, DurationInSeconds: 1})
+		expired := make(chan struct{})
+		go load.run(func() {
+			load.release()
+			close(expired)
+		})
+
+		select {
+		case <-expired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("load did not expire")
+		}
+		assert.Equal(t, cpuStopped, load.report().Status)
+	})
+
+	t.Run("should stop the current load when a new one starts", func(t *testing.T) {
+		ds := newDisruptions()
+		first := newCPULoad(model.CPULoad{Cores: 1, UtilisationPercent: 10})
+		ds.replace(cpuDisruption, nil, first)
+		ds.replace(cpuDisruption, nil, newCPULoad(model.CPULoad{Cores: 1, UtilisationPercent: 10}))
+
+		assert.True(t, first.stopped())
+		assert.Len(t, ds.stopKind(cpuDisruption), 1)
+		assert.Empty(t, ds.stopKind(cpuDisruption))
+	})
+}
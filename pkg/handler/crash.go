@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const defaultCrashMode = "fatal"
+
+// crashes are the ways in which dobby can kill itself,
+// each of them leaves a different signature for crash detection to find
+var crashes = map[string]func(exitCode int){
+	"fatal": func(int) {
+		log.Fatal("you asked me do so, killing myself :-)")
+	},
+	"panic": func(int) {
+		panic("you asked me do so, panicking :-)")
+	},
+	"exit": func(exitCode int) {
+		log.Printf("you asked me do so, exiting with code %d :-)", exitCode)
+		os.Exit(exitCode)
+	},
+	"nil": func(int) {
+		var request *proxyRequest
+		log.Println(request.Path)
+	},
+	"concurrent-map-write": func(int) {
+		// the writes have to run in parallel for the runtime to detect them
+		if runtime.GOMAXPROCS(0) < 2 {
+			runtime.GOMAXPROCS(2)
+		}
+		m := make(map[int]int)
+		for i := 0; i < 2; i++ {
+			go func() {
+				for j := 0; ; j++ {
+					m[j%100] = j
+				}
+			}()
+		}
+		select {}
+	},
+	"sigkill": signalItself(syscall.SIGKILL),
+	"sigsegv": signalItself(syscall.SIGSEGV),
+	"sigabrt": signalItself(syscall.SIGABRT),
+}
+
+func signalItself(signal syscall.Signal) func(int) {
+	return func(int) {
+		process, err := os.FindProcess(os.Getpid())
+		if err == nil {
+			err = process.Signal(signal)
+		}
+		if err != nil {
+			log.Printf("error when sending %s to itself: %s", signal, err)
+		}
+	}
+}
+
+func crashModes() []string {
+	modes := make([]string, 0, len(crashes))
+	for mode := range crashes {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// Crash will make dobby to kill itself
+// As dobby dies, the gin server also shuts down.
+// @Summary Suicide
+// @Description Make Dobby kill itself in the given mode
+// @Description fatal logs and exits with 1, panic panics in a goroutine, exit exits with exitCode,
+// @Description nil dereferences a nil pointer, concurrent-map-write causes a fatal runtime error,
+// @Description sigkill, sigsegv and sigabrt send the signal to itself
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param mode query string false "Mode of the crash, defaults to fatal - E.g. panic"
+// @Param exitCode query int false "Exit code for the exit mode, defaults to 1 - E.g. 137"
+// @Param delayInSeconds query int false "Crash after sometime (seconds) - E.g. 5"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/crash [put]
+func Crash(c *gin.Context) {
+	mode := c.DefaultQuery("mode", defaultCrashMode)
+	crash, ok := crashes[mode]
+	if !ok {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("mode should be one of %s", strings.Join(crashModes(), ", "))})
+		return
+	}
+	exitCode := 1
+	if exitCodeStr := c.Query("exitCode"); exitCodeStr != "" {
+		code, err := strconv.Atoi(exitCodeStr)
+		if err != nil || code < 0 || code > 255 {
+			c.JSON(http.StatusBadRequest, model.Error{Error: "exitCode should be between 0 and 255"})
+			return
+		}
+		exitCode = code
+	}
+	delay := 0
+	if delayStr := c.Query("delayInSeconds"); delayStr != "" {
+		seconds, err := strconv.Atoi(delayStr)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, model.Error{Error: "delayInSeconds should be a non negative integer"})
+			return
+		}
+		delay = seconds
+	}
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+	c.Writer.Flush()
+	go func() {
+		time.Sleep(time.Duration(delay) * time.Second)
+		crash(exitCode)
+	}()
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	healthProbe    = "health"
+	readinessProbe = "readiness"
+
+	defaultDependencyInterval = 5 * time.Second
+	defaultDependencyTimeout  = 2 * time.Second
+)
+
+// dependency is a downstream service which is checked periodically
+type dependency struct {
+	config model.Dependency
+	client httpClient
+	stop   chan struct{}
+	// track records the changes of health of the probes caused by the check
+	track func(cause string, change func())
+
+	mutex sync.RWMutex
+	check model.DependencyCheck
+}
+
+func (d *dependency) target() string {
+	if d.config.URL != "" {
+		return d.config.URL
+	}
+	return d.config.Address
+}
+
+func (d *dependency) timeout() time.Duration {
+	if d.config.TimeoutInSeconds > 0 {
+		return time.Duration(d.config.TimeoutInSeconds) * time.Second
+	}
+	return defaultDependencyTimeout
+}
+
+func (d *dependency) interval() time.Duration {
+	if d.config.IntervalInSeconds > 0 {
+		return time.Duration(d.config.IntervalInSeconds) * time.Second
+	}
+	return defaultDependencyInterval
+}
+
+func (d *dependency) affects(probe string) bool {
+	for _, p := range d.config.Probes {
+		if p == probe {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dependency) result() model.DependencyCheck {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return d.check
+}
+
+// run checks the dependency every interval till it is stopped
+func (d *dependency) run() {
+	ticker := time.NewTicker(d.interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.checkNow()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+func (d *dependency) checkNow() {
+	err := d.probe()
+	check := model.DependencyCheck{Name: d.config.Name, Target: d.target(), Healthy: err == nil, CheckedAt: time.Now()}
+	if err != nil {
+		check.Error = err.Error()
+	}
+	d.track(fmt.Sprintf("dependency %s", d.config.Name), func() {
+		d.mutex.Lock()
+		d.check = check
+		d.mutex.Unlock()
+	})
+}
+
+func (d *dependency) probe() error {
+	if d.config.Address != "" {
+		conn, err := net.DialTimeout("tcp", d.config.Address, d.timeout())
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout())
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, d.config.URL, nil)
+	if err != nil {
+		return fmt.Errorf("error when creating request for %s: %s", d.config.URL, err)
+	}
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	_ = response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s responded with status code %d", d.config.URL, response.StatusCode)
+	}
+	return nil
+}
+
+// dependencies holds the registered dependencies by their name
+type dependencies struct {
+	mutex  sync.RWMutex
+	byName map[string]*dependency
+}
+
+func newDependencies() *dependencies {
+	return &dependencies{byName: make(map[string]*dependency)}
+}
+
+func (ds *dependencies) add(d *dependency) bool {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	if _, ok := ds.byName[d.config.Name]; ok {
+		return false
+	}
+	ds.byName[d.config.Name] = d
+	return true
+}
+
+func (ds *dependencies) has(name string) bool {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+	_, ok := ds.byName[name]
+	return ok
+}
+
+func (ds *dependencies) remove(name string) bool {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	d, ok := ds.byName[name]
+	if !ok {
+		return false
+	}
+	close(d.stop)
+	delete(ds.byName, name)
+	return true
+}
+
+// checks returns the latest check of dependencies which affect the probe,
+// an empty probe returns all of them
+func (ds *dependencies) checks(probe string) []model.DependencyCheck {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+	checks := make([]model.DependencyCheck, 0, len(ds.byName))
+	for _, d := range ds.byName {
+		if probe == "" || d.affects(probe) {
+			checks = append(checks, d.result())
+		}
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	return checks
+}
+
+func (ds *dependencies) healthy(probe string) bool {
+	for _, check := range ds.checks(probe) {
+		if !check.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+func validateDependency(config model.Dependency) error {
+	if config.Name == "" {
+		return fmt.Errorf("name of the dependency is required")
+	}
+	if (config.URL == "") == (config.Address == "") {
+		return fmt.Errorf("either url or address of the dependency is required")
+	}
+	if len(config.Probes) == 0 {
+		return fmt.Errorf("probes affected by the dependency are required")
+	}
+	for _, probe := range config.Probes {
+		if probe != healthProbe && probe != readinessProbe {
+			return fmt.Errorf("probe %s is not one of %s, %s", probe, healthProbe, readinessProbe)
+		}
+	}
+	return nil
+}
+
+// AddDependency godoc
+// @Summary Add Dependency
+// @Description Register a downstream http url or tcp address which is checked periodically
+// @Description The probes listed in the dependency fail whenever it is unhealthy
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param body body model.Dependency true "'{name: postgres, address: postgres:5432, probes: [readiness]}'"
+// @Success 201 {object} model.DependencyCheck
+// @Failure 400 {object} model.Error
+// @Router /control/dependencies [post]
+func (h *Handler) AddDependency(c *gin.Context) {
+	var config model.Dependency
+	if err := json.NewDecoder(c.Request.Body).Decode(&config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("error when decoding request: %s", err.Error())})
+		return
+	}
+	if err := validateDependency(config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	d := &dependency{config: config, client: h.client, stop: make(chan struct{})}
+	d.track = func(cause string, change func()) {
+		h.trackProbes(cause, "", change)
+	}
+	if h.dependencies.has(config.Name) {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("dependency %s is already added", config.Name)})
+		return
+	}
+	// the dependency is checked before it is added, so that the probes never see it unchecked
+	d.checkNow()
+	added := false
+	h.trackProbes(controlCause(c), c.Request.RemoteAddr, func() {
+		added = h.dependencies.add(d)
+	})
+	if !added {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("dependency %s is already added", config.Name)})
+		return
+	}
+	go d.run()
+	c.JSON(http.StatusCreated, d.result())
+}
+
+// GetDependencies godoc
+// @Summary List Dependencies
+// @Description Get the latest check of every registered dependency
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.DependencyCheck
+// @Router /control/dependencies [get]
+func (h *Handler) GetDependencies(c *gin.Context) {
+	c.JSON(http.StatusOK, h.dependencies.checks(""))
+}
+
+// DeleteDependency godoc
+// @Summary Delete Dependency
+// @Description Stop checking a registered dependency
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param name path string true "Name of the dependency - E.g. postgres"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/dependencies/{name} [delete]
+func (h *Handler) DeleteDependency(c *gin.Context) {
+	name := c.Param("name")
+	removed := false
+	h.trackProbes(controlCause(c), c.Request.RemoteAddr, func() {
+		removed = h.dependencies.remove(name)
+	})
+	if !removed {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("dependency %s is not found", name)})
+		return
+	}
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	diskBlockSize  = 64 * 1024
+	diskIOInterval = 100 * time.Millisecond
+	// diskIOFileSize is the size after which the writes wrap around,
+	// so that the sustained writes do not fill the disk
+	diskIOFileSize = 64 * megabyte
+	// maxDiskWriteRate is the highest rate of the sustained writes (MB per second)
+	maxDiskWriteRate = 10000
+
+	diskIdle     = "idle"
+	diskFilling  = "filling"
+	diskHolding  = "holding"
+	diskReleased = "released"
+)
+
+// diskLoad fills the path with a file and writes to another file at the given rate
+type diskLoad struct {
+	config model.DiskLoad
+	block  []byte
+	stop   chan struct{}
+	once   sync.Once
+	wait   sync.WaitGroup
+	filled atomic.Int64
+	// written is the bytes written and synced by the sustained writes
+	written atomic.Int64
+
+	mutex  sync.RWMutex
+	files  []string
+	err    error
+	status string
+}
+
+func newDiskLoad(config model.DiskLoad) *diskLoad {
+	block := make([]byte, diskBlockSize)
+	for i := range block {
+		block[i] = byte(i)
+	}
+	return &diskLoad{config: config, block: block, stop: make(chan struct{}), status: diskFilling}
+}
+
+func (d *diskLoad) stopped() bool {
+	select {
+	case <-d.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *diskLoad) fail(err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.err = err
+}
+
+func (d *diskLoad) create(prefix string) (*os.File, error) {
+	file, err := os.CreateTemp(d.config.Path, prefix)
+	if err != nil {
+		return nil, err
+	}
+	d.mutex.Lock()
+	d.files = append(d.files, file.Name())
+	d.mutex.Unlock()
+	return file, nil
+}
+
+// fillTarget is the bytes to be written to fill the path
+func (d *diskLoad) fillTarget() (int64, error) {
+	if d.config.FillPercent == 0 {
+		return int64(d.config.FillInMB) * megabyte, nil
+	}
+	total, available, err := diskUsage(d.config.Path)
+	if err != nil {
+		return 0, err
+	}
+	target := int64(total)*int64(d.config.FillPercent)/100 - int64(total-available)
+	if target < 0 {
+		return 0, nil
+	}
+	return target, nil
+}
+
+func (d *diskLoad) fill() {
+	defer d.wait.Done()
+	defer d.setStatus(diskHolding)
+	target, err := d.fillTarget()
+	if err != nil || target == 0 {
+		d.fail(err)
+		return
+	}
+	file, err := d.create("dobby-fill-")
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	for d.filled.Load() < target && !d.stopped() {
+		block := d.block
+		if remaining := target - d.filled.Load(); remaining < int64(len(block)) {
+			block = block[:remaining]
+		}
+		n, err := file.Write(block)
+		d.filled.Add(int64(n))
+		if err != nil {
+			d.fail(err)
+			return
+		}
+	}
+	if err := file.Sync(); err != nil {
+		d.fail(err)
+	}
+}
+
+// write writes and syncs every interval to sustain the rate
+func (d *diskLoad) write() {
+	defer d.wait.Done()
+	file, err := d.create("dobby-io-")
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	perInterval := int64(d.config.WriteInMBPerSecond) * megabyte / int64(time.Second/diskIOInterval)
+	ticker := time.NewTicker(diskIOInterval)
+	defer ticker.Stop()
+	var offset int64
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+		var written int64
+		for written < perInterval {
+			if d.stopped() {
+				return
+			}
+			if offset >= diskIOFileSize {
+				if _, err := file.Seek(0, io.SeekStart); err != nil {
+					d.fail(err)
+					return
+				}
+				offset = 0
+			}
+			n, err := file.Write(d.block)
+			offset += int64(n)
+			written += int64(n)
+			if err != nil {
+				d.fail(err)
+				break
+			}
+		}
+		if err := file.Sync(); err != nil {
+			d.fail(err)
+			continue
+		}
+		d.written.Add(written)
+	}
+}
+
+func (d *diskLoad) run() {
+	if d.config.FillInMB > 0 || d.config.FillPercent > 0 {
+		d.wait.Add(1)
+		go d.fill()
+	} else {
+		d.setStatus(diskHolding)
+	}
+	if d.config.WriteInMBPerSecond > 0 {
+		d.wait.Add(1)
+		go d.write()
+	}
+}
+
+func (d *diskLoad) setStatus(status string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.status != diskReleased {
+		d.status = status
+	}
+}
+
+func (d *diskLoad) currentStatus() string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return d.status
+}
+
+// release stops the writes and removes the files
+func (d *diskLoad) release() {
+	d.once.Do(func() { close(d.stop) })
+	d.wait.Wait()
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, name := range d.files {
+		if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+			d.err = err
+		}
+	}
+	d.files = nil
+	d.filled.Store(0)
+	d.status = diskReleased
+}
+
+func (d *diskLoad) report() model.DiskLoad {
+	d.mutex.RLock()
+	report := d.config
+	report.Status = d.status
+	if d.err != nil {
+		report.Error = d.err.Error()
+	}
+	d.mutex.RUnlock()
+	report.FilledInMB = int(d.filled.Load() / megabyte)
+	report.WrittenInMB = int(d.written.Load() / megabyte)
+	if total, available, err := diskUsage(report.Path); err == nil && total > 0 {
+		report.UsedPercent = 100 * float64(total-available) / float64(total)
+	}
+	return report
+}
+
+func diskLoadFromQuery(c *gin.Context) (model.DiskLoad, error) {
+	config := model.DiskLoad{Path: c.DefaultQuery("path", os.TempDir())}
+	if info, err := os.Stat(config.Path); err != nil || !info.IsDir() {
+		return config, fmt.Errorf("path should be an existing directory")
+	}
+	nonNegative := func(name string) (int, error) {
+		value := c.Query(name)
+		if value == "" {
+			return 0, nil
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("%s should be a non negative integer", name)
+		}
+		return i, nil
+	}
+	var err error
+	if config.FillInMB, err = nonNegative("fillInMB"); err != nil {
+		return config, err
+	}
+	if config.FillPercent, err = nonNegative("fillPercent"); err != nil {
+		return config, err
+	}
+	if config.WriteInMBPerSecond, err = nonNegative("writeInMBPerSecond"); err != nil {
+		return config, err
+	}
+	if config.WriteInMBPerSecond > maxDiskWriteRate {
+		return config, fmt.Errorf("writeInMBPerSecond should be at most %d", maxDiskWriteRate)
+	}
+	if config.FillPercent > 100 {
+		return config, fmt.Errorf("fillPercent should be between 0 and 100")
+	}
+	if config.FillInMB > 0 && config.FillPercent > 0 {
+		return config, fmt.Errorf("only one of fillInMB and fillPercent is allowed")
+	}
+	if config.FillPercent > 0 {
+		if _, _, err := diskUsage(config.Path); err != nil {
+			return config, err
+		}
+	}
+	if config.FillInMB == 0 && config.FillPercent == 0 && config.WriteInMBPerSecond == 0 {
+		return config, fmt.Errorf("one of fillInMB, fillPercent or writeInMBPerSecond is required")
+	}
+	return config, nil
+}
+
+// GoTurboDisk godoc
+// @Summary Disk Spike
+// @Description Make Dobby fill the path with a file, and write to another file with a sync every 100ms at the given rate
+// @Description The file of the sustained writes wraps around at 64MB. It replaces the current disk load
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param path query string false "Directory to write to, defaults to the temp directory - E.g. /data"
+// @Param fillInMB query int false "Size of the file filling the path (MB) - E.g. 1024"
+// @Param fillPercent query int false "Fill till the filesystem of the path is used upto the percentage - E.g. 90"
+// @Param writeInMBPerSecond query int false "Rate of the sustained writes, at most 10000 (MB per second) - E.g. 20"
+// @Success 200 {object} model.DiskLoad
+// @Failure 400 {object} model.Error
+// @Router /control/goturbo/disk [put]
+func (h *Handler) GoTurboDisk(c *gin.Context) {
+	config, err := diskLoadFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("disk load on %s", config.Path), controlCause(c), c.Request.RemoteAddr)
+	load := newDiskLoad(config)
+	h.disruptions.replace(diskDisruption, disruptionParameters(c), load)
+	load.run()
+	c.JSON(http.StatusOK, load.report())
+}
+
+// GetDiskLoad godoc
+// @Summary Disk Load
+// @Description Get the disk filled and written by Dobby
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.DiskLoad
+// @Router /control/goturbo/disk [get]
+func (h *Handler) GetDiskLoad(c *gin.Context) {
+	d, ok := h.disruptions.find(diskDisruption)
+	if !ok {
+		c.JSON(http.StatusOK, model.DiskLoad{Status: diskIdle})
+		return
+	}
+	c.JSON(http.StatusOK, d.(*diskLoad).report())
+}
+
+// CleanDisk godoc
+// @Summary Clean Disk
+// @Description Make Dobby stop writing and remove the files it wrote
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/goturbo/disk [delete]
+func (h *Handler) CleanDisk(c *gin.Context) {
+	if len(h.disruptions.stopKind(diskDisruption)) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: "no disk load is running"})
+		return
+	}
+	h.history.record(disruptionSubject, "", "disk cleaned", controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestDiskLoad(t *testing.T) {
+	t.Run("should fill the path and remove the file when released", func(t *testing.T) {
+		path := t.TempDir()
+		load := newDiskLoad(model.DiskLoad{Path: path, FillInMB: 2})
+		load.run()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for load.currentStatus() != diskHolding && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		report := load.report()
+		assert.Equal(t, diskHolding, report.Status)
+		assert.Equal(t, 2, report.FilledInMB)
+		assert.Empty(t, report.Error)
+		files, _ := filepath.Glob(filepath.Join(path, "dobby-fill-*"))
+		assert.Len(t, files, 1)
+		info, err := os.Stat(files[0])
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2*megabyte), info.Size())
+
+		load.release()
+		assert.Equal(t, diskReleased, load.currentStatus())
+		files, _ = filepath.Glob(filepath.Join(path, "dobby-*"))
+		assert.Empty(t, files)
+	})
+
+	t.Run("should sustain writes at the given rate", func(t *testing.T) {
+		path := t.TempDir()
+		load := newDiskLoad(model.DiskLoad{Path: path, WriteInMBPerSecond: 10})
+		load.run()
+
+		time.Sleep(time.Second + 50*time.Millisecond)
+		load.release()
+
+		report := load.report()
+		assert.Empty(t, report.Error)
+		assert.InDelta(t, 10, report.WrittenInMB, 3)
+		files, _ := filepath.Glob(filepath.Join(path, "dobby-*"))
+		assert.Empty(t, files)
+	})
+
+	t.Run("should stop writing as soon as it is released", func(t *testing.T) {
+		load := newDiskLoad(model.DiskLoad{Path: t.TempDir(), WriteInMBPerSecond: maxDiskWriteRate})
+		load.run()
+		time.Sleep(150 * time.Millisecond)
+
+		start := time.Now()
+		load.release()
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package handler
+
+import "syscall"
+
+// diskUsage returns the total and available bytes of the filesystem of the path
+func diskUsage(path string) (total, available uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return stat.Blocks * uint64(stat.Bsize), stat.Bavail * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,8 @@
+package handler
+
+import "fmt"
+
+// diskUsage is not supported on windows
+func diskUsage(string) (total, available uint64, err error) {
+	return 0, 0, fmt.Errorf("usage of the filesystem is not supported on windows")
+}
@@ -1,64 +1,250 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/thecasualcoder/dobby/pkg/model"
-	"log"
-	"net/http"
 )
 
-// Crash will make dobby to kill itself
-// As dobby dies, the gin server also shuts down.
-// @Summary Suicide
-// @Description Make Dobby kill itself
-// @Tags Control
-// @Accept json
-// @Router /control/crash [put]
-func Crash(server *http.Server) func(ctx *gin.Context) {
-	return func(ctx *gin.Context) {
-		defer func() {
-			_ = server.Shutdown(ctx)
-		}()
+const (
+	memoryDisruption       = "memory"
+	cpuDisruption          = "cpu"
+	diskDisruption         = "disk"
+	gcDisruption           = "gc"
+	hangDisruption         = "hang"
+	latencyDisruption      = "latency"
+	faultDisruption        = "fault"
+	slowResponseDisruption = "slow response"
+	outboundDisruption     = "outbound"
+	memoryLeakDisruption   = "memory leak"
+)
+
+// disruption is a started disruption which can be stopped,
+// stopping it releases whatever it holds
+type disruption interface {
+	currentStatus() string
+	// stopped is true once it is released, or it ends by itself
+	stopped() bool
+	release()
+}
+
+type startedDisruption struct {
+	model.Disruption
+	disruption disruption
+}
+
+// disruptions keeps track of the started disruptions, so that they can be listed and stopped
+// Every kind of disruption which runs one at a time is replaced and stopped by its kind
+type disruptions struct {
+	mutex   sync.Mutex
+	lastID  int
+	started map[int]*startedDisruption
+}
+
+func newDisruptions() *disruptions {
+	return &disruptions{started: make(map[int]*startedDisruption)}
+}
+
+func (ds *disruptions) add(kind string, parameters map[string]string, d disruption) int {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	ds.lastID++
+	ds.started[ds.lastID] = &startedDisruption{
+		Disruption: model.Disruption{ID: ds.lastID, Type: kind, Parameters: parameters, StartedAt: time.Now()},
+		disruption: d,
+	}
+	return ds.lastID
+}
+
+// replace stops the started disruptions of the kind and adds the disruption in their place
+func (ds *disruptions) replace(kind string, parameters map[string]string, d disruption) int {
+	ds.mutex.Lock()
+	replaced := ds.removeKind(kind)
+	ds.lastID++
+	ds.started[ds.lastID] = &startedDisruption{
+		Disruption: model.Disruption{ID: ds.lastID, Type: kind, Parameters: parameters, StartedAt: time.Now()},
+		disruption: d,
+	}
+	id := ds.lastID
+	ds.mutex.Unlock()
+	for _, r := range replaced {
+		r.disruption.release()
+	}
+	return id
+}
+
+// find returns the latest started disruption of the kind
+func (ds *disruptions) find(kind string) (disruption, bool) {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	var latest *startedDisruption
+	for _, started := range ds.started {
+		if started.Type == kind && (latest == nil || started.ID > latest.ID) {
+			latest = started
+		}
+	}
+	if latest == nil {
+		return nil, false
+	}
+	return latest.disruption, true
+}
+
+// removeKind forgets the started disruptions of the kind, the caller holds the mutex
+func (ds *disruptions) removeKind(kind string) []*startedDisruption {
+	var removed []*startedDisruption
+	for id, started := range ds.started {
+		if started.Type == kind {
+			removed = append(removed, started)
+			delete(ds.started, id)
+		}
+	}
+	return removed
+}
+
+func (ds *disruptions) list() []model.Disruption {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	list := make([]model.Disruption, 0, len(ds.started))
+	for _, started := range ds.started {
+		d := started.Disruption
+		d.Status = started.disruption.currentStatus()
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// forget drops the disruption without stopping it, as it is stopped by other means
+func (ds *disruptions) forget(d disruption) {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	for id, started := range ds.started {
+		if started.disruption == d {
+			delete(ds.started, id)
+		}
+	}
+}
+
+// stop stops the disruption and forgets it,
+// it returns false if there is no such disruption or it was already stopped
+func (ds *disruptions) stop(id int) (model.Disruption, bool) {
+	ds.mutex.Lock()
+	started, ok := ds.started[id]
+	delete(ds.started, id)
+	ds.mutex.Unlock()
+	if !ok {
+		return model.Disruption{}, false
+	}
+	return started.Disruption, release(started)
+}
+
+// release releases the started disruption, it returns false if it was already stopped
+func release(started *startedDisruption) bool {
+	wasStopped := started.disruption.stopped()
+	started.disruption.release()
+	return !wasStopped
+}
+
+// stopKind stops the started disruptions of the kind and forgets them,
+// it returns the ones which were not already stopped
+func (ds *disruptions) stopKind(kind string) []model.Disruption {
+	ds.mutex.Lock()
+	removed := ds.removeKind(kind)
+	ds.mutex.Unlock()
+	return releaseAll(removed)
+}
 
-		log.Fatal("you asked me do so, killing myself :-)")
+func (ds *disruptions) stopAll() []model.Disruption {
+	ds.mutex.Lock()
+	started := make([]*startedDisruption, 0, len(ds.started))
+	for _, s := range ds.started {
+		started = append(started, s)
 	}
+	ds.started = make(map[int]*startedDisruption)
+	ds.mutex.Unlock()
+	return releaseAll(started)
 }
 
-// GoTurboMemory will make dobby go Turbo
-// Watch the video `https://youtu.be/TNjAZZ3vQ8o?t=14`
-// for more context on `Going Turbo`
-// @Summary Memory Spike
-// @Description Make Dobby create a memory spike
+func releaseAll(started []*startedDisruption) []model.Disruption {
+	stopped := make([]model.Disruption, 0, len(started))
+	for _, s := range started {
+		if release(s) {
+			stopped = append(stopped, s.Disruption)
+		}
+	}
+	sort.Slice(stopped, func(i, j int) bool { return stopped[i].ID < stopped[j].ID })
+	return stopped
+}
+
+// disruptionParameters are the query parameters with which the disruption was started
+func disruptionParameters(c *gin.Context) map[string]string {
+	query := c.Request.URL.Query()
+	if len(query) == 0 {
+		return nil
+	}
+	parameters := make(map[string]string, len(query))
+	for name := range query {
+		parameters[name] = query.Get(name)
+	}
+	return parameters
+}
+
+// GetDisruptions godoc
+// @Summary List Disruptions
+// @Description Get the disruptions started since Dobby started, or since they were last stopped
+// @Description Starting a disruption other than a rule replaces the one of its kind
 // @Tags Control
 // @Accept json
 // @Produce json
+// @Success 200 {array} model.Disruption
+// @Router /control/disruptions [get]
+func (h *Handler) GetDisruptions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.disruptions.list())
+}
+
+// StopDisruption godoc
+// @Summary Stop Disruption
+// @Description Stop a disruption and release whatever it holds, a disruption which already ended is not running
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the disruption - E.g. 1"
 // @Success 200 {object} model.ControlSuccess
-// @Router /control/goturbo/memory [put]
-func GoTurboMemory(c *gin.Context) {
-	memorySpike := []string{"qwertyuiopasdfghjklzxcvbnm"}
-	go func() {
-		for {
-			memorySpike = append(memorySpike, memorySpike...)
-		}
-	}()
-	c.JSON(200, model.ControlSuccess{Status: "success"})
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Router /control/disruptions/{id} [delete]
+func (h *Handler) StopDisruption(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: "id should be an integer"})
+		return
+	}
+	stopped, ok := h.disruptions.stop(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("disruption %d is not running", id)})
+		return
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("%s disruption %d stopped", stopped.Type, id), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
 }
 
-// GoTurboCPU will make dobby go Turbo
-// Watch the video `https://youtu.be/TNjAZZ3vQ8o?t=14`
-// for more context on `Going Turbo`
-// @Summary CPU Spike
-// @Description Make Dobby create a CPU spike
+// StopDisruptions godoc
+// @Summary Stop Disruptions
+// @Description Stop every disruption and release whatever they hold
 // @Tags Control
 // @Accept json
 // @Produce json
 // @Success 200 {object} model.ControlSuccess
-// @Router /control/goturbo/cpu [put]
-func GoTurboCPU(c *gin.Context) {
-	go func() {
-		for {
-			_ = 0
-		}
-	}()
-	c.JSON(200, model.ControlSuccess{Status: "success"})
+// @Router /control/disruptions [delete]
+func (h *Handler) StopDisruptions(c *gin.Context) {
+	for _, stopped := range h.disruptions.stopAll() {
+		h.history.record(disruptionSubject, "", fmt.Sprintf("%s disruption %d stopped", stopped.Type, stopped.ID), controlCause(c), c.Request.RemoteAddr)
+	}
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
 }
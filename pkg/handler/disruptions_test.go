@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDisruption struct {
+	released bool
+}
+
+func (f *fakeDisruption) currentStatus() string {
+	if f.released {
+		return "released"
+	}
+	return "running"
+}
+
+func (f *fakeDisruption) stopped() bool {
+	return f.released
+}
+
+func (f *fakeDisruption) release() {
+	f.released = true
+}
+
+func TestDisruptions(t *testing.T) {
+	t.Run("should list the disruptions with their current status", func(t *testing.T) {
+		ds := newDisruptions()
+		first, second := &fakeDisruption{}, &fakeDisruption{released: true}
+		ds.add("memory", map[string]string{"targetInMB": "10"}, first)
+		ds.add("cpu", nil, second)
+
+		list := ds.list()
+		assert.Len(t, list, 2)
+		assert.Equal(t, 1, list[0].ID)
+		assert.Equal(t, "memory", list[0].Type)
+		assert.Equal(t, "10", list[0].Parameters["targetInMB"])
+		assert.Equal(t, "running", list[0].Status)
+		assert.Equal(t, 2, list[1].ID)
+		assert.Equal(t, "released", list[1].Status)
+	})
+
+	t.Run("should release and forget a stopped disruption", func(t *testing.T) {
+		ds := newDisruptions()
+		d := &fakeDisruption{}
+		id := ds.add("memory", nil, d)
+
+		stopped, ok := ds.stop(id)
+		assert.True(t, ok)
+		assert.Equal(t, "memory", stopped.Type)
+		assert.True(t, d.released)
+		assert.Empty(t, ds.list())
+
+		_, ok = ds.stop(id)
+		assert.False(t, ok)
+	})
+
+	t.Run("should release every disruption when all of them are stopped", func(t *testing.T) {
+		ds := newDisruptions()
+		first, second := &fakeDisruption{}, &fakeDisruption{}
+		ds.add("memory", nil, first)
+		ds.add("cpu", nil, second)
+
+		assert.Len(t, ds.stopAll(), 2)
+		assert.True(t, first.released)
+		assert.True(t, second.released)
+		assert.Empty(t, ds.list())
+	})
+
+	t.Run("should replace and stop the disruptions of a kind", func(t *testing.T) {
+		ds := newDisruptions()
+		first, second, other := &fakeDisruption{}, &fakeDisruption{}, &fakeDisruption{}
+		ds.add("memory leak", nil, first)
+		ds.add("cpu", nil, other)
+		id := ds.replace("memory leak", nil, second)
+
+		assert.True(t, first.released)
+		assert.Len(t, ds.list(), 2)
+		found, ok := ds.find("memory leak")
+		assert.True(t, ok)
+		assert.Same(t, second, found)
+
+		stopped := ds.stopKind("memory leak")
+		assert.Len(t, stopped, 1)
+		assert.Equal(t, id, stopped[0].ID)
+		assert.True(t, second.released)
+		assert.False(t, other.released)
+		_, ok = ds.find("memory leak")
+		assert.False(t, ok)
+		assert.Empty(t, ds.stopKind("memory leak"))
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+// faultRule fails the percentage of the requests it matches with its status code and body
+type faultRule struct {
+	config model.FaultRule
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+func newFaultRule(config model.FaultRule) *faultRule {
+	return &faultRule{config: config, random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (f *faultRule) matches(r *http.Request) bool {
+	if !matchesRequest(f.config.Path, f.config.Method, r) {
+		return false
+	}
+	if f.config.Header == "" {
+		return true
+	}
+	values, ok := r.Header[http.CanonicalHeaderKey(f.config.Header)]
+	if !ok {
+		return false
+	}
+	if f.config.HeaderValue == "" {
+		return true
+	}
+	for _, value := range values {
+		if value == f.config.HeaderValue {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *faultRule) fails() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.random.Float64()*100 < f.config.Percentage
+}
+
+func validateFaultRule(config model.FaultRule) error {
+	if config.Percentage <= 0 || config.Percentage > 100 {
+		return fmt.Errorf("percentage should be more than 0 and at most 100")
+	}
+	if config.ConnectionFault != "" {
+		if err := validateConnectionFault(config.ConnectionFault, config.AfterBytes); err != nil {
+			return err
+		}
+	} else if config.StatusCode < 100 || config.StatusCode > 599 {
+		return fmt.Errorf("statusCode should be between 100 and 599")
+	}
+	if config.HeaderValue != "" && config.Header == "" {
+		return fmt.Errorf("header is required for the headerValue")
+	}
+	return validatePathPattern(config.Path)
+}
+
+// InjectFaults is the middleware which fails the requests matching a fault rule,
+// instead of letting them reach their handler
+func (h *Handler) InjectFaults(c *gin.Context) {
+	r := h.faultRules.first(c.Request)
+	if r == nil {
+		return
+	}
+	f := r.(*faultRule)
+	if !f.fails() {
+		return
+	}
+	if f.config.ConnectionFault != "" {
+		breakConnection(c, f.config.ConnectionFault, f.config.AfterBytes)
+		return
+	}
+	if f.config.Body == nil {
+		c.AbortWithStatus(f.config.StatusCode)
+		return
+	}
+	c.AbortWithStatusJSON(f.config.StatusCode, f.config.Body)
+}
+
+// AddFaultRule godoc
+// @Summary Add Fault
+// @Description Fail the percentage of the requests matching the path glob, the method and the header with the status code and body,
+// @Description or break their connection with the connection fault
+// @Description The earliest added rule matching a request applies, the control routes never fail
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param body body model.FaultRule true "'{path: /proxy/*, percentage: 30, statusCode: 503, body: {error: unavailable}}'"
+// @Success 201 {object} model.FaultRule
+// @Failure 400 {object} model.Error
+// @Router /control/faults [post]
+func (h *Handler) AddFaultRule(c *gin.Context) {
+	var config model.FaultRule
+	if err := json.NewDecoder(c.Request.Body).Decode(&config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("error when decoding request: %s", err.Error())})
+		return
+	}
+	if err := validateFaultRule(config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	id := h.faultRules.add(func(id int) rule {
+		config.ID = id
+		return newFaultRule(config)
+	})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("fault rule %d", id), controlCause(c), c.Request.RemoteAddr)
+	h.disruptions.add(faultDisruption, map[string]string{"rule": strconv.Itoa(id)}, ruleDisruption{rules: h.faultRules, id: id})
+	c.JSON(http.StatusCreated, config)
+}
+
+// GetFaultRules godoc
+// @Summary List Faults
+// @Description Get the rules failing the requests
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.FaultRule
+// @Router /control/faults [get]
+func (h *Handler) GetFaultRules(c *gin.Context) {
+	list := h.faultRules.list()
+	configs := make([]model.FaultRule, 0, len(list))
+	for _, r := range list {
+		configs = append(configs, r.(*faultRule).config)
+	}
+	c.JSON(http.StatusOK, configs)
+}
+
+// DeleteFaultRule godoc
+// @Summary Delete Fault
+// @Description Stop failing the requests matching a rule
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the rule - E.g. 1"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Router /control/faults/{id} [delete]
+func (h *Handler) DeleteFaultRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: "id should be an integer"})
+		return
+	}
+	if !h.faultRules.remove(id) {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("fault rule %d is not found", id)})
+		return
+	}
+	h.disruptions.forget(ruleDisruption{rules: h.faultRules, id: id})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("fault rule %d removed", id), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestFaultRule(t *testing.T) {
+	t.Run("should match the requests with the header", func(t *testing.T) {
+		anyValue := newFaultRule(model.FaultRule{Path: "/version", Header: "x-canary"})
+		withValue := newFaultRule(model.FaultRule{Header: "X-Canary", HeaderValue: "true"})
+
+		request := httptest.NewRequest("GET", "/version", nil)
+		assert.False(t, anyValue.matches(request))
+		request.Header.Set("X-Canary", "false")
+		assert.True(t, anyValue.matches(request))
+		assert.False(t, withValue.matches(request))
+		request.Header.Add("X-Canary", "true")
+		assert.True(t, withValue.matches(request))
+	})
+
+	t.Run("should fail the percentage of the requests", func(t *testing.T) {
+		always := newFaultRule(model.FaultRule{Percentage: 100})
+		half := newFaultRule(model.FaultRule{Percentage: 50})
+		failures := 0
+		for i := 0; i < 1000; i++ {
+			assert.True(t, always.fails())
+			if half.fails() {
+				failures++
+			}
+		}
+		assert.InDelta(t, 500, failures, 100)
+	})
+
+	t.Run("should not accept invalid rules", func(t *testing.T) {
+		assert.Error(t, validateFaultRule(model.FaultRule{Percentage: 0, StatusCode: 503}))
+		assert.Error(t, validateFaultRule(model.FaultRule{Percentage: 10, StatusCode: 42}))
+		assert.Error(t, validateFaultRule(model.FaultRule{Percentage: 10, StatusCode: 503, HeaderValue: "true"}))
+		assert.NoError(t, validateFaultRule(model.FaultRule{Percentage: 10, StatusCode: 503, Path: "/proxy/*"}))
+		assert.Error(t, validateFaultRule(model.FaultRule{Percentage: 10, ConnectionFault: "explode"}))
+		assert.Error(t, validateFaultRule(model.FaultRule{Percentage: 10, ConnectionFault: "close-after-bytes", AfterBytes: -1}))
+		assert.NoError(t, validateFaultRule(model.FaultRule{Percentage: 10, ConnectionFault: "reset"}))
+	})
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flakiness fails each probe request with the probability of failureRate
+type flakiness struct {
+	mutex       sync.Mutex
+	failureRate float64
+	random      *rand.Rand
+}
+
+// newFlakiness creates flakiness with the given failure rate,
+// a non zero seed makes the sequence of failures reproducible
+func newFlakiness(failureRate float64, seed int64) *flakiness {
+	if failureRate <= 0 {
+		return nil
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &flakiness{failureRate: failureRate, random: rand.New(rand.NewSource(seed))}
+}
+
+func (f *flakiness) fails() bool {
+	if f == nil {
+		return false
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.random.Float64() < f.failureRate
+}
+
+// mode describes the flakiness for the history
+func (f *flakiness) mode() string {
+	if f == nil {
+		return "not flaky"
+	}
+	return fmt.Sprintf("flaky with failure rate %g", f.failureRate)
+}
+
+func flakinessFromQuery(c *gin.Context) (*flakiness, error) {
+	failureRate, err := strconv.ParseFloat(c.Query("failureRate"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("failureRate should be between 0 and 1")
+	}
+	if err := ValidateFailureRate("failureRate", failureRate); err != nil {
+		return nil, err
+	}
+	var seed int64
+	if seedStr := c.Query("seed"); seedStr != "" {
+		seed, err = strconv.ParseInt(seedStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error converting the seed to int: %s", err.Error())
+		}
+	}
+	return newFlakiness(failureRate, seed), nil
+}
+
+// SetFailureRates makes health and readiness probes fail with the given probabilities,
+// a non zero seed makes the failures reproducible across runs
+// readiness is seeded with seed+1, so that the probes do not fail on the same requests
+func (h *Handler) SetFailureRates(healthFailureRate, readinessFailureRate float64, seed int64) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	h.state.updateProbe(healthProbe, func(p *probeState) {
+		p.flakiness = newFlakiness(healthFailureRate, seed)
+	})
+	h.state.updateProbe(readinessProbe, func(p *probeState) {
+		p.flakiness = newFlakiness(readinessFailureRate, seed+1)
+	})
+}
+
+// ValidateFailureRate returns an error if the failure rate is not between 0 and 1
+func ValidateFailureRate(name string, failureRate float64) error {
+	if failureRate < 0 || failureRate > 1 {
+		return fmt.Errorf("%s should be between 0 and 1", name)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlakiness_Fails(t *testing.T) {
+	t.Run("should fail the same requests for the same seed", func(t *testing.T) {
+		first := newFlakiness(0.5, 42)
+		second := newFlakiness(0.5, 42)
+
+		for i := 0; i < 100; i++ {
+			assert.Equal(t, first.fails(), second.fails())
+		}
+	})
+
+	t.Run("should fail roughly the configured rate of requests", func(t *testing.T) {
+		f := newFlakiness(0.3, 7)
+		failures := 0
+		for i := 0; i < 10000; i++ {
+			if f.fails() {
+				failures++
+			}
+		}
+		assert.InDelta(t, 3000, failures, 300)
+	})
+
+	t.Run("should never fail when failure rate is zero", func(t *testing.T) {
+		f := newFlakiness(0, 42)
+
+		assert.Nil(t, f)
+		assert.False(t, f.fails())
+	})
+
+	t.Run("should not fail the health and readiness probes on the same requests", func(t *testing.T) {
+		h := New(true, true, nil)
+		h.SetFailureRates(0.5, 0.5, 42)
+		health, readiness := h.state.probe(healthProbe).flakiness, h.state.probe(readinessProbe).flakiness
+
+		different := 0
+		for i := 0; i < 100; i++ {
+			if health.fails() != readiness.fails() {
+				different++
+			}
+		}
+		assert.Greater(t, different, 0)
+	})
+
+	t.Run("should not accept a failure rate outside 0 and 1", func(t *testing.T) {
+		assert.Error(t, ValidateFailureRate("health-failure-rate", 1.5))
+		assert.Error(t, ValidateFailureRate("health-failure-rate", -0.1))
+		assert.NoError(t, ValidateFailureRate("health-failure-rate", 0.5))
+	})
+}
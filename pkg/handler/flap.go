@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flap alternates a state between healthy and unhealthy on a schedule,
+// being healthy for the first dutyCycle fraction of every period
+type flap struct {
+	startedAt time.Time
+	period    time.Duration
+	dutyCycle float64
+	// until is when flapping stops, zero value flaps forever
+	until time.Time
+}
+
+// state returns whether the flap is healthy at the given time
+// and whether it is still flapping
+func (f *flap) state(now time.Time) (healthy bool, active bool) {
+	if f == nil || (!f.until.IsZero() && !now.Before(f.until)) {
+		return false, false
+	}
+	elapsed := now.Sub(f.startedAt) % f.period
+	return elapsed < time.Duration(float64(f.period)*f.dutyCycle), true
+}
+
+// nextToggle returns when the flap toggles next, or stops flapping if that is earlier
+func (f *flap) nextToggle(now time.Time) time.Time {
+	healthyFor := time.Duration(float64(f.period) * f.dutyCycle)
+	elapsed := now.Sub(f.startedAt) % f.period
+	next := now.Add(f.period - elapsed)
+	if elapsed < healthyFor {
+		next = now.Add(healthyFor - elapsed)
+	}
+	if !f.until.IsZero() && f.until.Before(next) {
+		return f.until
+	}
+	return next
+}
+
+func newFlap(c *gin.Context) (*flap, error) {
+	period, err := strconv.Atoi(c.Query("periodInSeconds"))
+	if err != nil || period <= 0 {
+		return nil, fmt.Errorf("periodInSeconds should be a positive integer")
+	}
+	dutyCycle := 0.5
+	if dutyCycleStr := c.Query("dutyCycle"); dutyCycleStr != "" {
+		dutyCycle, err = strconv.ParseFloat(dutyCycleStr, 64)
+		if err != nil || dutyCycle < 0 || dutyCycle > 1 {
+			return nil, fmt.Errorf("dutyCycle should be between 0 and 1")
+		}
+	}
+	now := time.Now()
+	f := &flap{startedAt: now, period: time.Duration(period) * time.Second, dutyCycle: dutyCycle}
+	if durationStr := c.Query("durationInSeconds"); durationStr != "" {
+		duration, err := strconv.Atoi(durationStr)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("durationInSeconds should be a positive integer")
+		}
+		f.until = now.Add(time.Duration(duration) * time.Second)
+	}
+	return f, nil
+}
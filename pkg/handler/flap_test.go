@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlap_State(t *testing.T) {
+	startedAt := time.Now()
+	f := &flap{startedAt: startedAt, period: 10 * time.Second, dutyCycle: 0.3, until: startedAt.Add(25 * time.Second)}
+
+	t.Run("should be healthy for the duty cycle of the period", func(t *testing.T) {
+		for _, offset := range []time.Duration{0, 2 * time.Second, 12 * time.Second} {
+			healthy, active := f.state(startedAt.Add(offset))
+			assert.True(t, active)
+			assert.True(t, healthy, "expected healthy at %s", offset)
+		}
+	})
+
+	t.Run("should be unhealthy for the rest of the period", func(t *testing.T) {
+		for _, offset := range []time.Duration{3 * time.Second, 9 * time.Second, 15 * time.Second} {
+			healthy, active := f.state(startedAt.Add(offset))
+			assert.True(t, active)
+			assert.False(t, healthy, "expected unhealthy at %s", offset)
+		}
+	})
+
+	t.Run("should stop flapping after the duration", func(t *testing.T) {
+		_, active := f.state(startedAt.Add(25 * time.Second))
+		assert.False(t, active)
+	})
+
+	t.Run("should not be active when flap is not configured", func(t *testing.T) {
+		var nilFlap *flap
+		_, active := nilFlap.state(startedAt)
+		assert.False(t, active)
+	})
+
+	t.Run("should toggle at the end of the duty cycle and the period, till the duration", func(t *testing.T) {
+		assert.Equal(t, startedAt.Add(3*time.Second), f.nextToggle(startedAt.Add(time.Second)))
+		assert.Equal(t, startedAt.Add(10*time.Second), f.nextToggle(startedAt.Add(5*time.Second)))
+		assert.Equal(t, startedAt.Add(25*time.Second), f.nextToggle(startedAt.Add(24*time.Second)))
+	})
+}
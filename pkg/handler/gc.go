@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	gcChurnInterval = 10 * time.Millisecond
+
+	gcIdle    = "idle"
+	gcRunning = "running"
+	gcStopped = "stopped"
+)
+
+// churnNode is a short lived object full of pointers, which the garbage collector has to scan
+type churnNode struct {
+	next     *churnNode
+	children [4]*churnNode
+	payload  [16]byte
+}
+
+var churnNodeSize = int(unsafe.Sizeof(churnNode{}))
+
+// gcPressure allocates short lived objects at the churn rate, forces garbage collection every interval,
+// and changes GOGC and the memory limit till it is stopped
+type gcPressure struct {
+	config model.GCPressure
+	stop   chan struct{}
+	once   sync.Once
+	wait   sync.WaitGroup
+	sink   atomic.Pointer[churnNode]
+
+	mutex               sync.Mutex
+	released            bool
+	previousGOGC        int
+	previousMemoryLimit int64
+}
+
+func newGCPressure(config model.GCPressure) *gcPressure {
+	return &gcPressure{config: config, stop: make(chan struct{}), previousGOGC: -2, previousMemoryLimit: -1}
+}
+
+func (g *gcPressure) churn() {
+	defer g.wait.Done()
+	perInterval := g.config.ChurnInMBPerSecond * megabyte / int(time.Second/gcChurnInterval) / churnNodeSize
+	ticker := time.NewTicker(gcChurnInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			g.sink.Store(nil)
+			return
+		case <-ticker.C:
+		}
+		var head *churnNode
+		for i := 0; i < perInterval; i++ {
+			head = &churnNode{next: head}
+			head.children[i%len(head.children)] = head.next
+		}
+		g.sink.Store(head)
+	}
+}
+
+func (g *gcPressure) collect() {
+	defer g.wait.Done()
+	ticker := time.NewTicker(time.Duration(g.config.GCIntervalInMilliseconds) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-g.stop:
+			return
+		case <-ticker.C:
+			runtime.GC()
+		}
+	}
+}
+
+// run applies the settings and starts the churn and the collections without blocking,
+// onExpiry is called if it is not stopped before the duration
+func (g *gcPressure) run(onExpiry func()) {
+	g.mutex.Lock()
+	if g.released {
+		g.mutex.Unlock()
+		return
+	}
+	if g.config.GOGC != nil {
+		g.previousGOGC = debug.SetGCPercent(*g.config.GOGC)
+	}
+	if g.config.MemoryLimitInMB > 0 {
+		g.previousMemoryLimit = debug.SetMemoryLimit(int64(g.config.MemoryLimitInMB) * megabyte)
+	}
+	g.mutex.Unlock()
+	if g.config.ChurnInMBPerSecond > 0 {
+		g.wait.Add(1)
+		go g.churn()
+	}
+	if g.config.GCIntervalInMilliseconds > 0 {
+		g.wait.Add(1)
+		go g.collect()
+	}
+	if g.config.DurationInSeconds == 0 {
+		return
+	}
+	go func() {
+		duration := time.NewTimer(time.Duration(g.config.DurationInSeconds) * time.Second)
+		defer duration.Stop()
+		select {
+		case <-g.stop:
+		case <-duration.C:
+			onExpiry()
+		}
+	}()
+}
+
+// release stops the churn and the collections, and restores GOGC and the memory limit
+func (g *gcPressure) release() {
+	g.once.Do(func() { close(g.stop) })
+	g.wait.Wait()
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.released {
+		return
+	}
+	g.released = true
+	if g.previousGOGC != -2 {
+		debug.SetGCPercent(g.previousGOGC)
+	}
+	if g.previousMemoryLimit != -1 {
+		debug.SetMemoryLimit(g.previousMemoryLimit)
+	}
+}
+
+func (g *gcPressure) stopped() bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.released
+}
+
+func (g *gcPressure) currentStatus() string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.released {
+		return gcStopped
+	}
+	return gcRunning
+}
+
+func (g *gcPressure) report() model.GCPressure {
+	report := g.config
+	report.Status = g.currentStatus()
+	return withGCStats(report)
+}
+
+func withGCStats(report model.GCPressure) model.GCPressure {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	report.NumGC = stats.NumGC
+	report.PauseTotalInMilliseconds = float64(stats.PauseTotalNs) / float64(time.Millisecond)
+	if stats.NumGC > 0 {
+		report.LastPauseInMicroseconds = float64(stats.PauseNs[(stats.NumGC+255)%256]) / float64(time.Microsecond)
+	}
+	return report
+}
+
+func gcPressureFromQuery(c *gin.Context) (model.GCPressure, error) {
+	config := model.GCPressure{}
+	nonNegative := func(name string) (int, error) {
+		value := c.Query(name)
+		if value == "" {
+			return 0, nil
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("%s should be a non negative integer", name)
+		}
+		return i, nil
+	}
+	var err error
+	if config.ChurnInMBPerSecond, err = nonNegative("churnInMBPerSecond"); err != nil {
+		return config, err
+	}
+	if config.GCIntervalInMilliseconds, err = nonNegative("gcIntervalInMilliseconds"); err != nil {
+		return config, err
+	}
+	if config.MemoryLimitInMB, err = nonNegative("memoryLimitInMB"); err != nil {
+		return config, err
+	}
+	if config.DurationInSeconds, err = nonNegative("durationInSeconds"); err != nil {
+		return config, err
+	}
+	if gogcStr := c.Query("gogc"); gogcStr != "" {
+		gogc, err := strconv.Atoi(gogcStr)
+		if err != nil || gogc < -1 || gogc > math.MaxInt32 {
+			return config, fmt.Errorf("gogc should be a non negative integer, or -1 to turn off the garbage collector")
+		}
+		config.GOGC = &gogc
+	}
+	if config.ChurnInMBPerSecond == 0 && config.GCIntervalInMilliseconds == 0 && config.GOGC == nil && config.MemoryLimitInMB == 0 {
+		return config, fmt.Errorf("one of churnInMBPerSecond, gcIntervalInMilliseconds, gogc or memoryLimitInMB is required")
+	}
+	return config, nil
+}
+
+// GoTurboGC godoc
+// @Summary GC Pressure
+// @Description Make Dobby churn short lived objects, force garbage collection every interval, and change GOGC and the memory limit
+// @Description to cause garbage collection pauses on every other endpoint. It replaces the current gc pressure
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param churnInMBPerSecond query int false "Rate of allocation of short lived objects (MB per second) - E.g. 200"
+// @Param gcIntervalInMilliseconds query int false "Force garbage collection every interval (milliseconds) - E.g. 500"
+// @Param gogc query int false "GOGC till it stops, -1 turns off the garbage collector - E.g. 20"
+// @Param memoryLimitInMB query int false "Soft memory limit of the go runtime till it stops (MB) - E.g. 256"
+// @Param durationInSeconds query int false "Stop after sometime (seconds) - E.g. 300"
+// @Success 200 {object} model.GCPressure
+// @Failure 400 {object} model.Error
+// @Router /control/goturbo/gc [put]
+func (h *Handler) GoTurboGC(c *gin.Context) {
+	config, err := gcPressureFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.history.record(disruptionSubject, "", "gc pressure", controlCause(c), c.Request.RemoteAddr)
+	pressure := newGCPressure(config)
+	h.disruptions.replace(gcDisruption, disruptionParameters(c), pressure)
+	pressure.run(func() {
+		pressure.release()
+		h.history.record(disruptionSubject, "", "gc pressure stopped", "duration elapsed", "")
+	})
+	c.JSON(http.StatusOK, pressure.report())
+}
+
+// GetGCPressure godoc
+// @Summary GC Stats
+// @Description Get the gc pressure created by Dobby, and the garbage collection stats of the go runtime
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.GCPressure
+// @Router /control/goturbo/gc [get]
+func (h *Handler) GetGCPressure(c *gin.Context) {
+	d, ok := h.disruptions.find(gcDisruption)
+	if !ok {
+		c.JSON(http.StatusOK, withGCStats(model.GCPressure{Status: gcIdle}))
+		return
+	}
+	c.JSON(http.StatusOK, d.(*gcPressure).report())
+}
+
+// StopGCPressure godoc
+// @Summary Stop GC Pressure
+// @Description Make Dobby stop the gc pressure, and restore GOGC and the memory limit
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/goturbo/gc [delete]
+func (h *Handler) StopGCPressure(c *gin.Context) {
+	if len(h.disruptions.stopKind(gcDisruption)) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: "no gc pressure is running"})
+		return
+	}
+	h.history.record(disruptionSubject, "", "gc pressure stopped", controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
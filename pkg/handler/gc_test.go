@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestGCPressure(t *testing.T) {
+	t.Run("should force garbage collection every interval", func(t *testing.T) {
+		pressure := newGCPressure(model.GCPressure{ChurnInMBPerSecond: 10, GCIntervalInMilliseconds: 50})
+		before := pressure.report().NumGC
+		pressure.run(func() {})
+
+		time.Sleep(300 * time.Millisecond)
+		pressure.release()
+
+		report := pressure.report()
+		assert.Equal(t, gcStopped, report.Status)
+		assert.True(t, report.NumGC >= before+4, "only %d collections", report.NumGC-before)
+	})
+
+	t.Run("should restore GOGC and the memory limit when released", func(t *testing.T) {
+		gogc := 20
+		previousGOGC := debug.SetGCPercent(100)
+		defer debug.SetGCPercent(previousGOGC)
+		pressure := newGCPressure(model.GCPressure{GOGC: &gogc, MemoryLimitInMB: 512})
+		pressure.run(func() {})
+
+		assert.Equal(t, 20, debug.SetGCPercent(20))
+		assert.Equal(t, int64(512*megabyte), debug.SetMemoryLimit(-1))
+
+		pressure.release()
+		assert.Equal(t, 100, debug.SetGCPercent(100))
+		assert.NotEqual(t, int64(512*megabyte), debug.SetMemoryLimit(-1))
+	})
+
+	t.Run("should not apply the settings once released", func(t *testing.T) {
+		gogc := 20
+		previousGOGC := debug.SetGCPercent(100)
+		defer debug.SetGCPercent(previousGOGC)
+		pressure := newGCPressure(model.GCPressure{GOGC: &gogc})
+		pressure.release()
+		pressure.run(func() {})
+
+		assert.Equal(t, 100, debug.SetGCPercent(100))
+	})
+}
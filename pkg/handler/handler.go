@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -13,10 +14,21 @@ import (
 
 // Handler is provides HandlerFunc for Gin Context
 type Handler struct {
-	isHealthy     bool
-	isReady       bool
-	client        httpClient
-	proxyRequests proxyRequests
+	state             *state
+	dependencies      *dependencies
+	checks            *checks
+	healthFormat      string
+	history           *history
+	timers            *timers
+	latencyRules      *rules
+	faultRules        *rules
+	slowResponseRules *rules
+	outboundRules     *rules
+	disruptions       *disruptions
+	client            httpClient
+
+	shutdownRequests chan int
+	adminListener    bool
 }
 
 type httpClient interface {
@@ -25,11 +37,24 @@ type httpClient interface {
 
 // New creates a new Handler
 func New(initialHealth, initialReadiness bool, httpClient httpClient) *Handler {
+	history := newHistory(defaultHistorySize)
+	history.record(healthProbe, "", strconv.FormatBool(initialHealth), initialCause, "")
+	history.record(readinessProbe, "", strconv.FormatBool(initialReadiness), initialCause, "")
+	outboundRules := newRules()
 	return &Handler{
-		isReady:       initialReadiness,
-		isHealthy:     initialHealth,
-		client:        httpClient,
-		proxyRequests: make(proxyRequests, 0),
+		state:             newState(initialHealth, initialReadiness),
+		dependencies:      newDependencies(),
+		checks:            newChecks(),
+		history:           history,
+		timers:            newTimers(),
+		latencyRules:      newRules(),
+		faultRules:        newRules(),
+		slowResponseRules: newRules(),
+		outboundRules:     outboundRules,
+		disruptions:       newDisruptions(),
+		client:            &outboundClient{client: httpClient, rules: outboundRules},
+
+		shutdownRequests: make(chan int, 1),
 	}
 }
 
@@ -41,6 +66,7 @@ type Context interface {
 	Status(code int)
 	GetURI() *url.URL
 	GetMethod() string
+	GetRemoteAddr() string
 	SendResponse(response *http.Response, url string)
 }
 
@@ -61,6 +87,10 @@ func (c defaultContext) GetMethod() string {
 	return c.ginContext.Request.Method
 }
 
+func (c defaultContext) GetRemoteAddr() string {
+	return c.ginContext.Request.RemoteAddr
+}
+
 func (c defaultContext) Status(code int) {
 	c.ginContext.Status(code)
 }
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	hangHanging = "hanging"
+	hangResumed = "resumed"
+)
+
+// hangEpisode blocks the requests, and optionally the new connections, till it is released
+type hangEpisode struct {
+	resume            chan struct{}
+	once              sync.Once
+	refuseConnections bool
+}
+
+func newHangEpisode(refuseConnections bool) *hangEpisode {
+	return &hangEpisode{resume: make(chan struct{}), refuseConnections: refuseConnections}
+}
+
+func (e *hangEpisode) release() {
+	e.once.Do(func() { close(e.resume) })
+}
+
+func (e *hangEpisode) stopped() bool {
+	return e.currentStatus() == hangResumed
+}
+
+func (e *hangEpisode) currentStatus() string {
+	select {
+	case <-e.resume:
+		return hangResumed
+	default:
+		return hangHanging
+	}
+}
+
+// currentHang returns the latest hang episode, or nil if dobby never hung since it was last resumed
+func (h *Handler) currentHang() *hangEpisode {
+	d, ok := h.disruptions.find(hangDisruption)
+	if !ok {
+		return nil
+	}
+	return d.(*hangEpisode)
+}
+
+// SetAdminListener tells dobby whether its control endpoints are served on an admin listener,
+// which is the only way to resume it once it hangs indefinitely
+func (h *Handler) SetAdminListener(enabled bool) {
+	h.adminListener = enabled
+}
+
+// WaitTillResponsive is the middleware which blocks the request while dobby hangs,
+// till it resumes or the client disconnects
+func (h *Handler) WaitTillResponsive(c *gin.Context) {
+	e := h.currentHang()
+	if e == nil {
+		return
+	}
+	select {
+	case <-e.resume:
+	case <-c.Request.Context().Done():
+		c.Abort()
+	}
+}
+
+// WaitToAccept blocks while dobby hangs refusing connections,
+// it is meant to be called before handing an accepted connection to the server
+func (h *Handler) WaitToAccept() {
+	if e := h.currentHang(); e != nil && e.refuseConnections {
+		<-e.resume
+	}
+}
+
+// Hang godoc
+// @Summary Hang
+// @Description Make Dobby block every request, and optionally stop accepting connections, while the process stays alive
+// @Description Only the admin listener keeps responding, durationInSeconds is required without it
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param durationInSeconds query int false "Resume after sometime (seconds), hangs till resumed by default - E.g. 60"
+// @Param refuseConnections query bool false "Stop accepting connections as well - E.g. true"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/hang [put]
+func (h *Handler) Hang(c *gin.Context) {
+	duration := 0
+	if durationStr := c.Query("durationInSeconds"); durationStr != "" {
+		seconds, err := strconv.Atoi(durationStr)
+		if err != nil || seconds <= 0 {
+			c.JSON(http.StatusBadRequest, model.Error{Error: "durationInSeconds should be a positive integer"})
+			return
+		}
+		duration = seconds
+	} else if !h.adminListener {
+		c.JSON(http.StatusBadRequest, model.Error{Error: "durationInSeconds is required when there is no admin listener"})
+		return
+	}
+	refuseConnections := false
+	if refuseStr := c.Query("refuseConnections"); refuseStr != "" {
+		refuse, err := strconv.ParseBool(refuseStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.Error{Error: "refuseConnections should be a boolean"})
+			return
+		}
+		refuseConnections = refuse
+	}
+	h.timers.cancelSubject(hangDisruption)
+	h.history.record(disruptionSubject, "", hangHanging, controlCause(c), c.Request.RemoteAddr)
+	e := newHangEpisode(refuseConnections)
+	h.disruptions.replace(hangDisruption, disruptionParameters(c), e)
+	if duration > 0 {
+		h.timers.schedule(hangDisruption, "resume responding", time.Duration(duration)*time.Second, func(id int) {
+			if e.currentStatus() == hangHanging {
+				e.release()
+				h.history.record(disruptionSubject, "", hangResumed, fmt.Sprintf("%s %d", resetCause, id), "")
+			}
+		})
+	}
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
+
+// Resume godoc
+// @Summary Resume
+// @Description Make Dobby respond again after it hangs
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/hang [delete]
+func (h *Handler) Resume(c *gin.Context) {
+	h.timers.cancelSubject(hangDisruption)
+	if len(h.disruptions.stopKind(hangDisruption)) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: "dobby is not hanging"})
+		return
+	}
+	h.history.record(disruptionSubject, "", hangResumed, controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
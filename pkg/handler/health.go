@@ -1,9 +1,8 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/thecasualcoder/dobby/pkg/model"
@@ -12,18 +11,29 @@ import (
 // Health return the dobby health status
 // @Summary Dobby Health
 // @Description Get Dobby's health status
+// @Description Renders application/health+json when asked for in the Accept header
 // @Tags Status
 // @Accept json
 // @Produce json
+// @Produce application/health+json
 // @Success 200 {object} model.Health
 // @Failure 500 {object} model.Health
 // @Router /health [get]
 func (h *Handler) Health(c *gin.Context) {
+	if !h.state.probe(healthProbe).latency.wait(c.Request.Context()) {
+		return
+	}
+	probe := h.state.probe(healthProbe)
+	healthy := !probe.flakiness.fails() && h.isHealthy(healthProbe, probe)
+	if h.wantsHealthJSON(c) {
+		h.renderHealthJSON(c, healthProbe, healthy, http.StatusInternalServerError, "application is not healthy")
+		return
+	}
 	statusCode := http.StatusOK
-	if !h.isHealthy {
+	if !healthy {
 		statusCode = http.StatusInternalServerError
 	}
-	c.JSON(statusCode, model.Health{Healthy: h.isHealthy})
+	c.JSON(statusCode, model.Health{Healthy: healthy, Dependencies: h.dependencies.checks(healthProbe)})
 }
 
 // MakeHealthPerfect godoc
@@ -35,7 +45,8 @@ func (h *Handler) Health(c *gin.Context) {
 // @Success 200 {object} model.ControlSuccess
 // @Router /control/health/perfect [put]
 func (h *Handler) MakeHealthPerfect(c *gin.Context) {
-	h.isHealthy = true
+	h.timers.cancelSubject(healthProbe)
+	h.resetProbe(healthProbe, true, controlCause(c), c.Request.RemoteAddr)
 	c.JSON(200, model.ControlSuccess{Status: "success"})
 }
 
@@ -49,17 +60,87 @@ func (h *Handler) MakeHealthPerfect(c *gin.Context) {
 // @Success 200 {object} model.ControlSuccess
 // @Router /control/health/sick [put]
 func (h *Handler) MakeHealthSick(c *gin.Context) {
-	h.isHealthy = false
-	setupResetFunction(c, func() {
-		h.isHealthy = true
+	h.timers.cancelSubject(healthProbe)
+	h.resetProbe(healthProbe, false, controlCause(c), c.Request.RemoteAddr)
+	remoteAddr := c.Request.RemoteAddr
+	h.scheduleReset(c, healthProbe, func(cause string) {
+		h.setProbeHealthy(healthProbe, true, cause, remoteAddr)
+	})
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// MakeHealthFlap godoc
+// @Summary Make Health Flap
+// @Description Make Dobby alternate between healthy and unhealthy
+// @Description Dobby is healthy for the first dutyCycle fraction of every period
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param periodInSeconds query int true "Length of one healthy and unhealthy cycle (seconds) - E.g. 10"
+// @Param dutyCycle query number false "Fraction of the period to be healthy, defaults to 0.5 - E.g. 0.8"
+// @Param durationInSeconds query int false "Stop flapping after sometime (seconds) - E.g. 60"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/health/flap [put]
+func (h *Handler) MakeHealthFlap(c *gin.Context) {
+	f, err := newFlap(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.setProbeMode(c, healthProbe, fmt.Sprintf("flapping every %s", f.period), func(p *probeState) {
+		p.flap = f
+	})
+	go h.recordFlaps(healthProbe, f)
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// MakeHealthFlaky godoc
+// @Summary Make Health Flaky
+// @Description Make every request to Dobby's health fail with the given probability
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param failureRate query number true "Probability of a health request to fail, 0 disables it - E.g. 0.3"
+// @Param seed query int false "Seed to make the failures reproducible - E.g. 42"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/health/flaky [put]
+func (h *Handler) MakeHealthFlaky(c *gin.Context) {
+	f, err := flakinessFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.setProbeMode(c, healthProbe, f.mode(), func(p *probeState) {
+		p.flakiness = f
 	})
 	c.JSON(200, model.ControlSuccess{Status: "success"})
 }
 
-func setupResetFunction(c *gin.Context, afterFunc func()) {
-	const resetInSecondsQueryParam = "resetInSeconds"
-	resetTimer := c.Query(resetInSecondsQueryParam)
-	if resetInSeconds, err := strconv.Atoi(resetTimer); err == nil && resetInSeconds != 0 {
-		go time.AfterFunc(time.Second*time.Duration(resetInSeconds), afterFunc)
+// MakeHealthSlow godoc
+// @Summary Make Health Slow
+// @Description Make Dobby respond slowly to health requests
+// @Description Delays by a fixed delay, a random delay between minDelay and maxDelay, or hangs till the client disconnects
+// @Description Calling without any of them makes health respond promptly again
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param delay query int false "Fixed delay (milliseconds) - E.g. 1000"
+// @Param minDelay query int false "Minimum delay (milliseconds) - E.g. 500"
+// @Param maxDelay query int false "Maximum delay (milliseconds) - E.g. 3000"
+// @Param hang query bool false "Hang till the client disconnects - E.g. true"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/health/slow [put]
+func (h *Handler) MakeHealthSlow(c *gin.Context) {
+	l, err := latencyFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
 	}
+	h.setProbeMode(c, healthProbe, l.mode(), func(p *probeState) {
+		p.latency = l
+	})
+	c.JSON(200, model.ControlSuccess{Status: "success"})
 }
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/config"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	// HealthJSONFormat renders the probes as application/health+json
+	HealthJSONFormat = "health+json"
+	// JSONFormat renders the probes as plain json
+	JSONFormat = "json"
+
+	healthJSONContentType = "application/health+json"
+
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// checks holds the named checks set through the control api
+type checks struct {
+	mutex  sync.RWMutex
+	byName map[string]model.Check
+}
+
+func newChecks() *checks {
+	return &checks{byName: make(map[string]model.Check)}
+}
+
+func (cs *checks) set(name string, check model.Check) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.byName[name] = check
+}
+
+func (cs *checks) remove(name string) bool {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if _, ok := cs.byName[name]; !ok {
+		return false
+	}
+	delete(cs.byName, name)
+	return true
+}
+
+func reportedBy(check model.Check, probe string) bool {
+	if len(check.Probes) == 0 {
+		return true
+	}
+	for _, p := range check.Probes {
+		if p == probe {
+			return true
+		}
+	}
+	return false
+}
+
+// forProbe returns the checks reported by the probe, an empty probe returns all of them
+func (cs *checks) forProbe(probe string) map[string]model.Check {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+	result := make(map[string]model.Check)
+	for name, check := range cs.byName {
+		if probe == "" || reportedBy(check, probe) {
+			result[name] = check
+		}
+	}
+	return result
+}
+
+// passing returns false if any check reported by the probe fails, warnings still pass
+func (cs *checks) passing(probe string) bool {
+	for _, check := range cs.forProbe(probe) {
+		if check.Status == checkFail {
+			return false
+		}
+	}
+	return true
+}
+
+// SetHealthFormat sets the default rendering of /health and /readiness (json|health+json),
+// which is overridden by an Accept header of application/health+json
+func (h *Handler) SetHealthFormat(format string) {
+	h.healthFormat = format
+}
+
+// ValidateHealthFormat returns an error if the format is not one of json and health+json
+func ValidateHealthFormat(format string) error {
+	if format != JSONFormat && format != HealthJSONFormat {
+		return fmt.Errorf("health-format should be one of %s, %s", JSONFormat, HealthJSONFormat)
+	}
+	return nil
+}
+
+func (h *Handler) wantsHealthJSON(c *gin.Context) bool {
+	return h.healthFormat == HealthJSONFormat || strings.Contains(c.GetHeader("Accept"), healthJSONContentType)
+}
+
+// renderHealthJSON renders the probe as application/health+json,
+// with the given status code and output when it is not healthy
+func (h *Handler) renderHealthJSON(c *gin.Context, probe string, healthy bool, failureStatusCode int, failureOutput string) {
+	response := model.HealthJSON{
+		Status:    checkPass,
+		Version:   appVersion(),
+		ReleaseID: config.BuildVersion(),
+		ServiceID: os.Getenv("HOSTNAME"),
+		Checks:    make(map[string][]model.HealthCheck),
+	}
+	for name, check := range h.checks.forProbe(probe) {
+		response.Checks[name] = append(response.Checks[name], check.HealthCheck)
+		if check.Status == checkWarn {
+			response.Status = checkWarn
+		}
+	}
+	for _, dependency := range h.dependencies.checks(probe) {
+		check := model.HealthCheck{
+			ComponentID:   dependency.Target,
+			ComponentType: "component",
+			Status:        checkPass,
+			Time:          dependency.CheckedAt.Format(time.RFC3339),
+			Output:        dependency.Error,
+		}
+		if !dependency.Healthy {
+			check.Status = checkFail
+		}
+		response.Checks[dependency.Name] = append(response.Checks[dependency.Name], check)
+	}
+	statusCode := http.StatusOK
+	if !healthy {
+		statusCode = failureStatusCode
+		response.Status = checkFail
+		response.Output = failureOutput
+	}
+	c.Header("Content-Type", healthJSONContentType)
+	c.JSON(statusCode, response)
+}
+
+// SetCheck godoc
+// @Summary Set Check
+// @Description Set a named check reported in the application/health+json rendering of the probes
+// @Description A check which fails makes the probes reporting it fail, a check which warns still returns 200
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param name path string true "Name of the check - E.g. postgres:responseTime"
+// @Param body body model.Check true "'{status: warn, componentType: datastore, observedValue: 250, observedUnit: ms, probes: [readiness]}'"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/checks/{name} [put]
+func (h *Handler) SetCheck(c *gin.Context) {
+	var check model.Check
+	if err := json.NewDecoder(c.Request.Body).Decode(&check); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("error when decoding request: %s", err.Error())})
+		return
+	}
+	if check.Status != checkPass && check.Status != checkWarn && check.Status != checkFail {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("status should be one of %s, %s, %s", checkPass, checkWarn, checkFail)})
+		return
+	}
+	for _, probe := range check.Probes {
+		if probe != healthProbe && probe != readinessProbe {
+			c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("probe %s is not one of %s, %s", probe, healthProbe, readinessProbe)})
+			return
+		}
+	}
+	if check.Time == "" {
+		check.Time = time.Now().Format(time.RFC3339)
+	}
+	h.trackProbes(controlCause(c), c.Request.RemoteAddr, func() {
+		h.checks.set(c.Param("name"), check)
+	})
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
+
+// GetChecks godoc
+// @Summary List Checks
+// @Description Get the named checks set through the control api
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]model.Check
+// @Router /control/checks [get]
+func (h *Handler) GetChecks(c *gin.Context) {
+	c.JSON(http.StatusOK, h.checks.forProbe(""))
+}
+
+// DeleteCheck godoc
+// @Summary Delete Check
+// @Description Delete a named check
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param name path string true "Name of the check - E.g. postgres:responseTime"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/checks/{name} [delete]
+func (h *Handler) DeleteCheck(c *gin.Context) {
+	name := c.Param("name")
+	removed := false
+	h.trackProbes(controlCause(c), c.Request.RemoteAddr, func() {
+		removed = h.checks.remove(name)
+	})
+	if !removed {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("check %s is not found", name)})
+		return
+	}
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
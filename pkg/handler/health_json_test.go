@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHealthFormat(t *testing.T) {
+	t.Run("should accept only json and health+json", func(t *testing.T) {
+		assert.NoError(t, ValidateHealthFormat(JSONFormat))
+		assert.NoError(t, ValidateHealthFormat(HealthJSONFormat))
+		assert.EqualError(t, ValidateHealthFormat("xml"), "health-format should be one of json, health+json")
+	})
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	defaultHistorySize = 100
+
+	proxySubject      = "proxy"
+	disruptionSubject = "disruption"
+	startupSubject    = "startup"
+
+	initialCause = "initial state"
+	resetCause   = "reset timer"
+	flapCause    = "flap"
+)
+
+// history is a bounded ring buffer of state transitions,
+// which overwrites the oldest transition when it is full
+type history struct {
+	mutex       sync.RWMutex
+	transitions []model.Transition
+	next        int
+	full        bool
+}
+
+func newHistory(size int) *history {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	return &history{transitions: make([]model.Transition, size)}
+}
+
+func (hs *history) record(subject, from, to, cause, remoteAddr string) {
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+	hs.transitions[hs.next] = model.Transition{
+		Time:       time.Now(),
+		Subject:    subject,
+		From:       from,
+		To:         to,
+		Cause:      cause,
+		RemoteAddr: remoteAddr,
+	}
+	hs.next = (hs.next + 1) % len(hs.transitions)
+	if hs.next == 0 {
+		hs.full = true
+	}
+}
+
+func (hs *history) recordBool(subject string, from, to bool, cause, remoteAddr string) {
+	hs.record(subject, strconv.FormatBool(from), strconv.FormatBool(to), cause, remoteAddr)
+}
+
+// list returns the transitions from the oldest to the latest
+func (hs *history) list() []model.Transition {
+	hs.mutex.RLock()
+	defer hs.mutex.RUnlock()
+	return hs.ordered()
+}
+
+func (hs *history) ordered() []model.Transition {
+	if !hs.full {
+		return append([]model.Transition{}, hs.transitions[:hs.next]...)
+	}
+	return append(append([]model.Transition{}, hs.transitions[hs.next:]...), hs.transitions[:hs.next]...)
+}
+
+// resize changes the size of the history, keeping the latest transitions
+func (hs *history) resize(size int) {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+	transitions := hs.ordered()
+	if len(transitions) > size {
+		transitions = transitions[len(transitions)-size:]
+	}
+	hs.transitions = make([]model.Transition, size)
+	hs.next = copy(hs.transitions, transitions) % size
+	hs.full = len(transitions) == size
+}
+
+// SetHistorySize sets the number of state transitions kept in the history
+func (h *Handler) SetHistorySize(size int) {
+	h.history.resize(size)
+}
+
+func controlCause(c *gin.Context) string {
+	return fmt.Sprintf("%s %s", c.Request.Method, c.Request.URL.RequestURI())
+}
+
+// GetHistory godoc
+// @Summary State History
+// @Description Get the latest state transitions of Dobby, from the oldest to the latest
+// @Description Includes changes to health, readiness and startup, whether caused by the control api, flapping, dependencies or checks,
+// @Description changes to the modes of the probes, proxies being added or deleted and disruptions being started
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Transition
+// @Router /control/history [get]
+func (h *Handler) GetHistory(c *gin.Context) {
+	c.JSON(http.StatusOK, h.history.list())
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	t.Run("should list transitions from the oldest to the latest", func(t *testing.T) {
+		hs := newHistory(3)
+		hs.record(healthProbe, "true", "false", "first", "")
+		hs.record(healthProbe, "false", "true", "second", "")
+
+		transitions := hs.list()
+
+		assert.Len(t, transitions, 2)
+		assert.Equal(t, "first", transitions[0].Cause)
+		assert.Equal(t, "second", transitions[1].Cause)
+	})
+
+	t.Run("should overwrite the oldest transitions when full", func(t *testing.T) {
+		hs := newHistory(3)
+		for _, cause := range []string{"first", "second", "third", "fourth", "fifth"} {
+			hs.record(healthProbe, "true", "false", cause, "")
+		}
+
+		transitions := hs.list()
+
+		assert.Len(t, transitions, 3)
+		assert.Equal(t, "third", transitions[0].Cause)
+		assert.Equal(t, "fifth", transitions[2].Cause)
+	})
+
+	t.Run("should keep the latest transitions when resized", func(t *testing.T) {
+		hs := newHistory(3)
+		for _, cause := range []string{"first", "second", "third"} {
+			hs.record(healthProbe, "true", "false", cause, "")
+		}
+
+		hs.resize(2)
+		hs.record(healthProbe, "true", "false", "fourth", "")
+
+		transitions := hs.list()
+		assert.Len(t, transitions, 2)
+		assert.Equal(t, "third", transitions[0].Cause)
+		assert.Equal(t, "fourth", transitions[1].Cause)
+	})
+
+	t.Run("should resize while transitions are recorded", func(t *testing.T) {
+		hs := newHistory(3)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				hs.record(healthProbe, "true", "false", "concurrent", "")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for size := 1; size <= 100; size++ {
+				hs.resize(size)
+			}
+		}()
+		wg.Wait()
+
+		assert.NotEmpty(t, hs.list())
+	})
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// latency delays a probe response by a fixed duration, a random duration
+// between min and max, or hangs till the client disconnects
+type latency struct {
+	min  time.Duration
+	max  time.Duration
+	hang bool
+}
+
+// wait blocks for the configured delay and returns false
+// if the client went away before that
+func (l *latency) wait(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	if l.hang {
+		<-ctx.Done()
+		return false
+	}
+	delay := l.min
+	if l.max > l.min {
+		delay += time.Duration(rand.Int63n(int64(l.max - l.min)))
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// mode describes the latency for the history
+func (l *latency) mode() string {
+	switch {
+	case l == nil:
+		return "prompt"
+	case l.hang:
+		return "hanging"
+	case l.max > l.min:
+		return fmt.Sprintf("slow by %s to %s", l.min, l.max)
+	default:
+		return fmt.Sprintf("slow by %s", l.min)
+	}
+}
+
+func latencyFromQuery(c *gin.Context) (*latency, error) {
+	if hang, err := strconv.ParseBool(c.Query("hang")); err == nil && hang {
+		return &latency{hang: true}, nil
+	}
+	millis := func(name string) (time.Duration, error) {
+		value := c.Query(name)
+		if value == "" {
+			return 0, nil
+		}
+		delay, err := strconv.Atoi(value)
+		if err != nil || delay < 0 {
+			return 0, fmt.Errorf("%s should be a non negative integer", name)
+		}
+		return time.Duration(delay) * time.Millisecond, nil
+	}
+	delay, err := millis("delay")
+	if err != nil {
+		return nil, err
+	}
+	if delay > 0 {
+		return &latency{min: delay, max: delay}, nil
+	}
+	minDelay, err := millis("minDelay")
+	if err != nil {
+		return nil, err
+	}
+	maxDelay, err := millis("maxDelay")
+	if err != nil {
+		return nil, err
+	}
+	if maxDelay < minDelay {
+		return nil, fmt.Errorf("maxDelay should not be less than minDelay")
+	}
+	if maxDelay == 0 {
+		return nil, nil
+	}
+	return &latency{min: minDelay, max: maxDelay}, nil
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	fixedDistribution       = "fixed"
+	uniformDistribution     = "uniform"
+	normalDistribution      = "normal"
+	exponentialDistribution = "exponential"
+)
+
+// latencyRule delays the requests it matches by a delay sampled from its distribution
+type latencyRule struct {
+	config model.LatencyRule
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+func newLatencyRule(config model.LatencyRule) *latencyRule {
+	return &latencyRule{config: config, random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (l *latencyRule) matches(r *http.Request) bool {
+	return matchesRequest(l.config.Path, l.config.Method, r)
+}
+
+func (l *latencyRule) delay() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	var millis float64
+	switch l.config.Distribution {
+	case fixedDistribution:
+		millis = float64(l.config.DelayInMilliseconds)
+	case uniformDistribution:
+		minDelay, maxDelay := l.config.MinDelayInMilliseconds, l.config.MaxDelayInMilliseconds
+		millis = float64(minDelay + l.random.Intn(maxDelay-minDelay+1))
+	case normalDistribution:
+		millis = float64(l.config.DelayInMilliseconds) + l.random.NormFloat64()*float64(l.config.StdDevInMilliseconds)
+	case exponentialDistribution:
+		millis = l.random.ExpFloat64() * float64(l.config.DelayInMilliseconds)
+	}
+	if l.config.SpikeRate > 0 && l.random.Float64() < l.config.SpikeRate {
+		millis += float64(l.config.SpikeDelayInMilliseconds)
+	}
+	if millis < 0 {
+		return 0
+	}
+	return time.Duration(millis * float64(time.Millisecond))
+}
+
+func validateLatencyRule(config model.LatencyRule) error {
+	if config.DelayInMilliseconds < 0 || config.MinDelayInMilliseconds < 0 || config.MaxDelayInMilliseconds < 0 ||
+		config.StdDevInMilliseconds < 0 || config.SpikeDelayInMilliseconds < 0 {
+		return fmt.Errorf("delays should not be negative")
+	}
+	switch config.Distribution {
+	case fixedDistribution, normalDistribution, exponentialDistribution:
+	case uniformDistribution:
+		if config.MaxDelayInMilliseconds < config.MinDelayInMilliseconds {
+			return fmt.Errorf("maxDelayInMilliseconds should not be less than minDelayInMilliseconds")
+		}
+	default:
+		return fmt.Errorf("distribution should be one of %s, %s, %s, %s",
+			fixedDistribution, uniformDistribution, normalDistribution, exponentialDistribution)
+	}
+	if config.SpikeRate < 0 || config.SpikeRate > 1 {
+		return fmt.Errorf("spikeRate should be between 0 and 1")
+	}
+	return validatePathPattern(config.Path)
+}
+
+// InjectLatency is the middleware which delays the requests matching a latency rule,
+// till the delay elapses or the client disconnects
+func (h *Handler) InjectLatency(c *gin.Context) {
+	r := h.latencyRules.first(c.Request)
+	if r == nil {
+		return
+	}
+	timer := time.NewTimer(r.(*latencyRule).delay())
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-c.Request.Context().Done():
+		c.Abort()
+	}
+}
+
+// AddLatencyRule godoc
+// @Summary Add Latency
+// @Description Delay the requests matching the path glob and the method, by a delay from the distribution
+// @Description The earliest added rule matching a request applies, the control routes are never delayed
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param body body model.LatencyRule true "'{path: /call, method: POST, distribution: normal, delayInMilliseconds: 200, stdDevInMilliseconds: 50}'"
+// @Success 201 {object} model.LatencyRule
+// @Failure 400 {object} model.Error
+// @Router /control/latency [post]
+func (h *Handler) AddLatencyRule(c *gin.Context) {
+	var config model.LatencyRule
+	if err := json.NewDecoder(c.Request.Body).Decode(&config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("error when decoding request: %s", err.Error())})
+		return
+	}
+	if err := validateLatencyRule(config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	id := h.latencyRules.add(func(id int) rule {
+		config.ID = id
+		return newLatencyRule(config)
+	})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("latency rule %d", id), controlCause(c), c.Request.RemoteAddr)
+	h.disruptions.add(latencyDisruption, map[string]string{"rule": strconv.Itoa(id)}, ruleDisruption{rules: h.latencyRules, id: id})
+	c.JSON(http.StatusCreated, config)
+}
+
+// GetLatencyRules godoc
+// @Summary List Latency
+// @Description Get the rules delaying the requests
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.LatencyRule
+// @Router /control/latency [get]
+func (h *Handler) GetLatencyRules(c *gin.Context) {
+	list := h.latencyRules.list()
+	configs := make([]model.LatencyRule, 0, len(list))
+	for _, r := range list {
+		configs = append(configs, r.(*latencyRule).config)
+	}
+	c.JSON(http.StatusOK, configs)
+}
+
+// DeleteLatencyRule godoc
+// @Summary Delete Latency
+// @Description Stop delaying the requests matching a rule
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the rule - E.g. 1"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Router /control/latency/{id} [delete]
+func (h *Handler) DeleteLatencyRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: "id should be an integer"})
+		return
+	}
+	if !h.latencyRules.remove(id) {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("latency rule %d is not found", id)})
+		return
+	}
+	h.disruptions.forget(ruleDisruption{rules: h.latencyRules, id: id})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("latency rule %d removed", id), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
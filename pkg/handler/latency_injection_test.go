@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestLatencyRule(t *testing.T) {
+	t.Run("should sample the delay from the distribution", func(t *testing.T) {
+		fixed := newLatencyRule(model.LatencyRule{Distribution: fixedDistribution, DelayInMilliseconds: 200})
+		assert.Equal(t, 200*time.Millisecond, fixed.delay())
+
+		uniform := newLatencyRule(model.LatencyRule{Distribution: uniformDistribution, MinDelayInMilliseconds: 100, MaxDelayInMilliseconds: 300})
+		exponential := newLatencyRule(model.LatencyRule{Distribution: exponentialDistribution, DelayInMilliseconds: 100})
+		normal := newLatencyRule(model.LatencyRule{Distribution: normalDistribution, DelayInMilliseconds: 100, StdDevInMilliseconds: 10})
+		var exponentialTotal, normalTotal time.Duration
+		for i := 0; i < 1000; i++ {
+			delay := uniform.delay()
+			assert.True(t, delay >= 100*time.Millisecond && delay <= 300*time.Millisecond)
+			exponentialTotal += exponential.delay()
+			normalTotal += normal.delay()
+		}
+		assert.InDelta(t, 100, exponentialTotal.Milliseconds()/1000, 20)
+		assert.InDelta(t, 100, normalTotal.Milliseconds()/1000, 5)
+	})
+
+	t.Run("should add the spike with the spike rate", func(t *testing.T) {
+		spiky := newLatencyRule(model.LatencyRule{Distribution: fixedDistribution, DelayInMilliseconds: 10, SpikeRate: 1, SpikeDelayInMilliseconds: 500})
+		assert.Equal(t, 510*time.Millisecond, spiky.delay())
+	})
+
+	t.Run("should not accept an unknown distribution or an invalid glob", func(t *testing.T) {
+		assert.Error(t, validateLatencyRule(model.LatencyRule{Distribution: "poisson"}))
+		assert.Error(t, validateLatencyRule(model.LatencyRule{Distribution: fixedDistribution, Path: "/proxy/["}))
+		assert.Error(t, validateLatencyRule(model.LatencyRule{Distribution: uniformDistribution, MinDelayInMilliseconds: 10}))
+		assert.NoError(t, validateLatencyRule(model.LatencyRule{Distribution: fixedDistribution, Path: "/proxy/*"}))
+	})
+}
+
+func TestRules(t *testing.T) {
+	t.Run("should apply the earliest added rule matching the request", func(t *testing.T) {
+		rs := newRules()
+		rs.add(func(id int) rule {
+			return newLatencyRule(model.LatencyRule{ID: id, Path: "/proxy/*", Method: "POST"})
+		})
+		rs.add(func(id int) rule {
+			return newLatencyRule(model.LatencyRule{ID: id, Path: "/proxy/*"})
+		})
+		rs.add(func(id int) rule { return newLatencyRule(model.LatencyRule{ID: id}) })
+
+		assert.Equal(t, 1, rs.first(httptest.NewRequest("POST", "/proxy/time", nil)).(*latencyRule).config.ID)
+		assert.Equal(t, 2, rs.first(httptest.NewRequest("GET", "/proxy/time", nil)).(*latencyRule).config.ID)
+		assert.Equal(t, 3, rs.first(httptest.NewRequest("GET", "/health", nil)).(*latencyRule).config.ID)
+		assert.Nil(t, rs.first(httptest.NewRequest("GET", "/control/latency", nil)))
+
+		assert.True(t, rs.remove(1))
+		assert.False(t, rs.remove(1))
+		assert.Equal(t, 2, rs.first(httptest.NewRequest("POST", "/proxy/time", nil)).(*latencyRule).config.ID)
+	})
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	goroutinesLeak = "goroutines"
+	filesLeak      = "files"
+	socketsLeak    = "sockets"
+
+	defaultLeakRate = 10
+	defaultLeakMax  = 1000
+	maxLeakRate     = 1000
+
+	leakIdle     = "idle"
+	leakLeaking  = "leaking"
+	leakHolding  = "holding"
+	leakReleased = "released"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// leaker opens a resource which is held till it is closed
+type leaker interface {
+	open() (io.Closer, error)
+	io.Closer
+}
+
+// goroutineLeaker leaks goroutines blocked forever on their channel
+type goroutineLeaker struct{}
+
+func (goroutineLeaker) open() (io.Closer, error) {
+	blocked := make(chan struct{})
+	go func() {
+		<-blocked
+	}()
+	return closerFunc(func() error {
+		close(blocked)
+		return nil
+	}), nil
+}
+
+func (goroutineLeaker) Close() error {
+	return nil
+}
+
+// fileLeaker leaks file descriptors of the null device
+type fileLeaker struct{}
+
+func (fileLeaker) open() (io.Closer, error) {
+	return os.Open(os.DevNull)
+}
+
+func (fileLeaker) Close() error {
+	return nil
+}
+
+// socketLeaker leaks tcp connections to itself, each of them holds two file descriptors
+type socketLeaker struct {
+	listener net.Listener
+
+	mutex    sync.Mutex
+	accepted []net.Conn
+}
+
+func newSocketLeaker() (*socketLeaker, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &socketLeaker{listener: listener}
+	go s.accept()
+	return s, nil
+}
+
+func (s *socketLeaker) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.accepted = append(s.accepted, conn)
+		s.mutex.Unlock()
+	}
+}
+
+func (s *socketLeaker) open() (io.Closer, error) {
+	return net.Dial("tcp", s.listener.Addr().String())
+}
+
+func (s *socketLeaker) Close() error {
+	err := s.listener.Close()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, conn := range s.accepted {
+		_ = conn.Close()
+	}
+	s.accepted = nil
+	return err
+}
+
+var leakers = map[string]func() (leaker, error){
+	goroutinesLeak: func() (leaker, error) { return goroutineLeaker{}, nil },
+	filesLeak:      func() (leaker, error) { return fileLeaker{}, nil },
+	socketsLeak:    func() (leaker, error) { return newSocketLeaker() },
+}
+
+func leakKinds() []string {
+	kinds := make([]string, 0, len(leakers))
+	for kind := range leakers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// leak opens resources at the given rate till the max, and holds them till it is released
+type leak struct {
+	config model.Leak
+	leaker leaker
+	stop   chan struct{}
+	once   sync.Once
+
+	mutex     sync.RWMutex
+	resources []io.Closer
+	err       error
+	status    string
+}
+
+func newLeak(config model.Leak, leaker leaker) *leak {
+	return &leak{config: config, leaker: leaker, stop: make(chan struct{}), status: leakLeaking}
+}
+
+func (l *leak) run() {
+	ticker := time.NewTicker(time.Second / time.Duration(l.config.RatePerSecond))
+	defer ticker.Stop()
+	for l.leaked() < l.config.Max {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+		resource, err := l.leaker.open()
+		l.mutex.Lock()
+		if err != nil {
+			l.err = err
+		} else if l.status == leakReleased {
+			_ = resource.Close()
+		} else {
+			l.resources = append(l.resources, resource)
+		}
+		l.mutex.Unlock()
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.status == leakLeaking {
+		l.status = leakHolding
+	}
+}
+
+func (l *leak) leaked() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return len(l.resources)
+}
+
+func (l *leak) currentStatus() string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.status
+}
+
+func (l *leak) stopped() bool {
+	return l.currentStatus() == leakReleased
+}
+
+// release closes the leaked resources
+func (l *leak) release() {
+	l.once.Do(func() { close(l.stop) })
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, resource := range l.resources {
+		_ = resource.Close()
+	}
+	_ = l.leaker.Close()
+	l.resources = nil
+	l.status = leakReleased
+}
+
+func (l *leak) report() model.Leak {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	report := l.config
+	report.Status = l.status
+	report.Leaked = len(l.resources)
+	if l.err != nil {
+		report.Error = l.err.Error()
+	}
+	report.NumGoroutine = runtime.NumGoroutine()
+	return report
+}
+
+// leakDisruption is the kind of the disruption leaking the kind of resources
+func leakDisruption(kind string) string {
+	return fmt.Sprintf("%s leak", kind)
+}
+
+func leakFromQuery(c *gin.Context) (model.Leak, error) {
+	config := model.Leak{Kind: c.Param("kind"), RatePerSecond: defaultLeakRate, Max: defaultLeakMax}
+	if _, ok := leakers[config.Kind]; !ok {
+		return config, fmt.Errorf("kind should be one of %s", strings.Join(leakKinds(), ", "))
+	}
+	if rateStr := c.Query("ratePerSecond"); rateStr != "" {
+		rate, err := strconv.Atoi(rateStr)
+		if err != nil || rate <= 0 || rate > maxLeakRate {
+			return config, fmt.Errorf("ratePerSecond should be between 1 and %d", maxLeakRate)
+		}
+		config.RatePerSecond = rate
+	}
+	if maxStr := c.Query("max"); maxStr != "" {
+		limit, err := strconv.Atoi(maxStr)
+		if err != nil || limit <= 0 {
+			return config, fmt.Errorf("max should be a positive integer")
+		}
+		config.Max = limit
+	}
+	return config, nil
+}
+
+// Leak godoc
+// @Summary Leak
+// @Description Make Dobby leak goroutines, files or sockets at the given rate till the max, replacing the current leak of the kind
+// @Description goroutines are blocked forever, files are opened and sockets are connected to itself (two file descriptors each)
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param kind path string true "Kind of the leak - E.g. sockets"
+// @Param ratePerSecond query int false "Number leaked every second, defaults to 10 and at most 1000 - E.g. 5"
+// @Param max query int false "Maximum leaked, defaults to 1000 - E.g. 500"
+// @Success 200 {object} model.Leak
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /control/leak/{kind} [put]
+func (h *Handler) Leak(c *gin.Context) {
+	config, err := leakFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	leaker, err := leakers[config.Kind]()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{Error: fmt.Sprintf("error when starting to leak %s: %s", config.Kind, err)})
+		return
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("%s leak", config.Kind), controlCause(c), c.Request.RemoteAddr)
+	l := newLeak(config, leaker)
+	h.disruptions.replace(leakDisruption(config.Kind), disruptionParameters(c), l)
+	go l.run()
+	c.JSON(http.StatusOK, l.report())
+}
+
+// GetLeak godoc
+// @Summary Leaked
+// @Description Get the number of goroutines, files or sockets leaked by Dobby
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param kind path string true "Kind of the leak - E.g. sockets"
+// @Success 200 {object} model.Leak
+// @Failure 400 {object} model.Error
+// @Router /control/leak/{kind} [get]
+func (h *Handler) GetLeak(c *gin.Context) {
+	kind := c.Param("kind")
+	if _, ok := leakers[kind]; !ok {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("kind should be one of %s", strings.Join(leakKinds(), ", "))})
+		return
+	}
+	d, ok := h.disruptions.find(leakDisruption(kind))
+	if !ok {
+		c.JSON(http.StatusOK, model.Leak{Status: leakIdle, Kind: kind, NumGoroutine: runtime.NumGoroutine()})
+		return
+	}
+	c.JSON(http.StatusOK, d.(*leak).report())
+}
+
+// FreeLeak godoc
+// @Summary Free Leak
+// @Description Make Dobby close the goroutines, files or sockets it leaked
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param kind path string true "Kind of the leak - E.g. sockets"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/leak/{kind} [delete]
+func (h *Handler) FreeLeak(c *gin.Context) {
+	kind := c.Param("kind")
+	if len(h.disruptions.stopKind(leakDisruption(kind))) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("no %s are leaked", kind)})
+		return
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("%s leak freed", kind), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func waitForLeakStatus(t *testing.T, l *leak, status string) {
+	deadline := time.Now().Add(2 * time.Second)
+	for l.currentStatus() != status {
+		if time.Now().After(deadline) {
+			t.Fatalf("leak is %s, expected %s", l.currentStatus(), status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLeak(t *testing.T) {
+	for kind := range leakers {
+		t.Run("should leak "+kind+" till the max and free them", func(t *testing.T) {
+			leaker, err := leakers[kind]()
+			assert.NoError(t, err)
+			l := newLeak(model.Leak{Kind: kind, RatePerSecond: 100, Max: 5}, leaker)
+			go l.run()
+
+			waitForLeakStatus(t, l, leakHolding)
+			report := l.report()
+			assert.Equal(t, 5, report.Leaked)
+			assert.Empty(t, report.Error)
+
+			l.release()
+			assert.Equal(t, leakReleased, l.currentStatus())
+			assert.Equal(t, 0, l.report().Leaked)
+		})
+	}
+
+	t.Run("should unblock the leaked goroutines when freed", func(t *testing.T) {
+		l := newLeak(model.Leak{Kind: goroutinesLeak, RatePerSecond: 100, Max: 10}, goroutineLeaker{})
+		go l.run()
+		waitForLeakStatus(t, l, leakHolding)
+		leaking := runtime.NumGoroutine()
+
+		l.release()
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > leaking-10 {
+			if time.Now().After(deadline) {
+				t.Fatalf("%d goroutines are running, expected at most %d", runtime.NumGoroutine(), leaking-10)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
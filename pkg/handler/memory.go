@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	megabyte           = 1 << 20
+	pageSize           = 4096
+	memoryRampInterval = 100 * time.Millisecond
+
+	memoryIdle     = "idle"
+	memoryRamping  = "ramping"
+	memoryHolding  = "holding"
+	memoryReleased = "released"
+)
+
+// memoryLoad allocates memory in chunks of a megabyte till the target,
+// a target of 0 keeps allocating till dobby is killed
+type memoryLoad struct {
+	config model.MemoryLoad
+	stop   chan struct{}
+	once   sync.Once
+
+	mutex  sync.RWMutex
+	chunks [][]byte
+	status string
+}
+
+func newMemoryLoad(config model.MemoryLoad) *memoryLoad {
+	return &memoryLoad{config: config, stop: make(chan struct{}), status: memoryRamping}
+}
+
+func (m *memoryLoad) allocated() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.chunks)
+}
+
+func (m *memoryLoad) currentStatus() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.status
+}
+
+func (m *memoryLoad) stopped() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.status == memoryReleased
+}
+
+// newChunk allocates a megabyte, touching its pages makes them count towards the resident set size
+func newChunk(touch bool) []byte {
+	chunk := make([]byte, megabyte)
+	if touch {
+		for i := 0; i < len(chunk); i += pageSize {
+			chunk[i] = 1
+		}
+	}
+	return chunk
+}
+
+func (m *memoryLoad) allocate() {
+	chunk := newChunk(m.config.Touch)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.status == memoryRamping {
+		m.chunks = append(m.chunks, chunk)
+	}
+}
+
+func (m *memoryLoad) setStatus(status string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.status != memoryReleased {
+		m.status = status
+	}
+}
+
+// run ramps up the allocation to the target and holds it,
+// onExpiry is called if it is not released before the hold duration
+func (m *memoryLoad) run(onExpiry func()) {
+	startedAt := time.Now()
+	ticker := time.NewTicker(memoryRampInterval)
+	defer ticker.Stop()
+	for m.config.TargetInMB == 0 || m.allocated() < m.config.TargetInMB {
+		select {
+		case <-m.stop:
+			return
+		default:
+		}
+		ramp := m.config.RampInMBPerSecond
+		if ramp > 0 && m.allocated() >= int(time.Since(startedAt).Seconds()*float64(ramp)) {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+			continue
+		}
+		m.allocate()
+	}
+	m.setStatus(memoryHolding)
+	if m.config.HoldInSeconds == 0 {
+		return
+	}
+	hold := time.NewTimer(time.Duration(m.config.HoldInSeconds) * time.Second)
+	defer hold.Stop()
+	select {
+	case <-m.stop:
+	case <-hold.C:
+		onExpiry()
+	}
+}
+
+// release frees the allocated memory and returns it to the os
+func (m *memoryLoad) release() {
+	m.once.Do(func() { close(m.stop) })
+	m.mutex.Lock()
+	m.chunks = nil
+	m.status = memoryReleased
+	m.mutex.Unlock()
+	debug.FreeOSMemory()
+}
+
+func (m *memoryLoad) report() model.MemoryLoad {
+	m.mutex.RLock()
+	report := m.config
+	report.Status = m.status
+	report.AllocatedInMB = len(m.chunks)
+	m.mutex.RUnlock()
+	return withMemoryStats(report)
+}
+
+func withMemoryStats(report model.MemoryLoad) model.MemoryLoad {
+	report.HeapInUseInMB, report.SysInMB = memoryStats()
+	return report
+}
+
+// memoryStats are the heap in use and the memory obtained from the os in MB
+func memoryStats() (uint64, uint64) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse / megabyte, stats.Sys / megabyte
+}
+
+func memoryLoadFromQuery(c *gin.Context) (model.MemoryLoad, error) {
+	config := model.MemoryLoad{}
+	nonNegative := func(name string) (int, error) {
+		value := c.Query(name)
+		if value == "" {
+			return 0, nil
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("%s should be a non negative integer", name)
+		}
+		return i, nil
+	}
+	var err error
+	if config.TargetInMB, err = nonNegative("targetInMB"); err != nil {
+		return config, err
+	}
+	if config.RampInMBPerSecond, err = nonNegative("rampInMBPerSecond"); err != nil {
+		return config, err
+	}
+	if config.HoldInSeconds, err = nonNegative("holdInSeconds"); err != nil {
+		return config, err
+	}
+	if touchStr := c.Query("touch"); touchStr != "" {
+		if config.Touch, err = strconv.ParseBool(touchStr); err != nil {
+			return config, fmt.Errorf("touch should be a boolean")
+		}
+	}
+	return config, nil
+}
+
+// GoTurboMemory will make dobby go Turbo
+// Watch the video `https://youtu.be/TNjAZZ3vQ8o?t=14`
+// for more context on `Going Turbo`
+// @Summary Memory Spike
+// @Description Make Dobby allocate memory till the target and hold it, replacing the current allocation
+// @Description Without a target it keeps allocating till it is killed
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param targetInMB query int false "Memory to allocate (MB) - E.g. 512"
+// @Param rampInMBPerSecond query int false "Rate of allocation, all at once by default (MB per second) - E.g. 64"
+// @Param holdInSeconds query int false "Release the memory after holding it for sometime (seconds), held till released by default - E.g. 600"
+// @Param touch query bool false "Write to the allocated pages so that they count towards the resident set size - E.g. true"
+// @Success 200 {object} model.MemoryLoad
+// @Failure 400 {object} model.Error
+// @Router /control/goturbo/memory [put]
+func (h *Handler) GoTurboMemory(c *gin.Context) {
+	config, err := memoryLoadFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	description := "memory spike"
+	if config.TargetInMB > 0 {
+		description = fmt.Sprintf("memory load of %d MB", config.TargetInMB)
+	}
+	h.history.record(disruptionSubject, "", description, controlCause(c), c.Request.RemoteAddr)
+	load := newMemoryLoad(config)
+	h.disruptions.replace(memoryDisruption, disruptionParameters(c), load)
+	go load.run(func() {
+		load.release()
+		h.history.record(disruptionSubject, "", "memory released", "hold expired", "")
+	})
+	c.JSON(http.StatusOK, load.report())
+}
+
+// GetMemoryLoad godoc
+// @Summary Memory Load
+// @Description Get the memory allocated by Dobby
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.MemoryLoad
+// @Router /control/goturbo/memory [get]
+func (h *Handler) GetMemoryLoad(c *gin.Context) {
+	d, ok := h.disruptions.find(memoryDisruption)
+	if !ok {
+		c.JSON(http.StatusOK, withMemoryStats(model.MemoryLoad{Status: memoryIdle}))
+		return
+	}
+	c.JSON(http.StatusOK, d.(*memoryLoad).report())
+}
+
+// ReleaseMemory godoc
+// @Summary Release Memory
+// @Description Make Dobby release the memory it allocated
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/goturbo/memory [delete]
+func (h *Handler) ReleaseMemory(c *gin.Context) {
+	if len(h.disruptions.stopKind(memoryDisruption)) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: "no memory is held"})
+		return
+	}
+	h.history.record(disruptionSubject, "", "memory released", controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
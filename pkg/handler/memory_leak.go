@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	memoryLeakKind             = "memory"
+	defaultMemoryLeakPerMinute = 10
+	maxMemoryLeakPerMinute     = 60000
+)
+
+// memoryLeak grows the retained heap a megabyte at a time at the given rate till the ceiling,
+// a ceiling of 0 keeps it growing till dobby is killed
+type memoryLeak struct {
+	config model.MemoryLeak
+	stop   chan struct{}
+	once   sync.Once
+
+	mutex     sync.RWMutex
+	chunks    [][]byte
+	status    string
+	startedAt time.Time
+}
+
+func newMemoryLeak(config model.MemoryLeak) *memoryLeak {
+	return &memoryLeak{config: config, stop: make(chan struct{}), status: leakLeaking, startedAt: time.Now()}
+}
+
+func (m *memoryLeak) interval() time.Duration {
+	return time.Minute / time.Duration(m.config.RateInMBPerMinute)
+}
+
+func (m *memoryLeak) leaked() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.chunks)
+}
+
+func (m *memoryLeak) run() {
+	ticker := time.NewTicker(m.interval())
+	defer ticker.Stop()
+	for m.config.CeilingInMB == 0 || m.leaked() < m.config.CeilingInMB {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+		chunk := newChunk(m.config.Touch)
+		m.mutex.Lock()
+		if m.status == leakLeaking {
+			m.chunks = append(m.chunks, chunk)
+		}
+		m.mutex.Unlock()
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.status == leakLeaking {
+		m.status = leakHolding
+	}
+}
+
+func (m *memoryLeak) currentStatus() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.status
+}
+
+func (m *memoryLeak) stopped() bool {
+	return m.currentStatus() == leakReleased
+}
+
+// release frees the leaked memory and returns it to the os
+func (m *memoryLeak) release() {
+	m.once.Do(func() { close(m.stop) })
+	m.mutex.Lock()
+	m.chunks = nil
+	m.status = leakReleased
+	m.mutex.Unlock()
+	debug.FreeOSMemory()
+}
+
+func (m *memoryLeak) report() model.MemoryLeak {
+	m.mutex.RLock()
+	report := m.config
+	report.Status = m.status
+	report.LeakedInMB = len(m.chunks)
+	m.mutex.RUnlock()
+	if report.CeilingInMB > 0 {
+		report.ProgressPercent = float64(report.LeakedInMB) * 100 / float64(report.CeilingInMB)
+		if report.Status == leakLeaking {
+			report.CeilingInSeconds = int((time.Duration(report.CeilingInMB-report.LeakedInMB) * m.interval()).Seconds())
+		}
+	}
+	report.HeapInUseInMB, report.SysInMB = memoryStats()
+	return report
+}
+
+func memoryLeakFromQuery(c *gin.Context) (model.MemoryLeak, error) {
+	config := model.MemoryLeak{RateInMBPerMinute: defaultMemoryLeakPerMinute}
+	if rateStr := c.Query("rateInMBPerMinute"); rateStr != "" {
+		rate, err := strconv.Atoi(rateStr)
+		if err != nil || rate <= 0 || rate > maxMemoryLeakPerMinute {
+			return config, fmt.Errorf("rateInMBPerMinute should be between 1 and %d", maxMemoryLeakPerMinute)
+		}
+		config.RateInMBPerMinute = rate
+	}
+	if ceilingStr := c.Query("ceilingInMB"); ceilingStr != "" {
+		ceiling, err := strconv.Atoi(ceilingStr)
+		if err != nil || ceiling < 0 {
+			return config, fmt.Errorf("ceilingInMB should be a non negative integer")
+		}
+		config.CeilingInMB = ceiling
+	}
+	if touchStr := c.Query("touch"); touchStr != "" {
+		touch, err := strconv.ParseBool(touchStr)
+		if err != nil {
+			return config, fmt.Errorf("touch should be a boolean")
+		}
+		config.Touch = touch
+	}
+	return config, nil
+}
+
+// LeakMemory godoc
+// @Summary Leak Memory
+// @Description Make Dobby leak memory at the given rate till the ceiling, replacing the current memory leak
+// @Description Without a ceiling it keeps leaking till it is killed
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param rateInMBPerMinute query int false "Memory leaked every minute, defaults to 10 and at most 60000 (MB) - E.g. 5"
+// @Param ceilingInMB query int false "Memory at which the leak stops growing (MB) - E.g. 1024"
+// @Param touch query bool false "Write to the leaked pages so that they count towards the resident set size - E.g. true"
+// @Success 200 {object} model.MemoryLeak
+// @Failure 400 {object} model.Error
+// @Router /control/leak/memory [put]
+func (h *Handler) LeakMemory(c *gin.Context) {
+	config, err := memoryLeakFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("%s leak of %d MB per minute", memoryLeakKind, config.RateInMBPerMinute), controlCause(c), c.Request.RemoteAddr)
+	leak := newMemoryLeak(config)
+	h.disruptions.replace(memoryLeakDisruption, disruptionParameters(c), leak)
+	go leak.run()
+	c.JSON(http.StatusOK, leak.report())
+}
+
+// GetMemoryLeak godoc
+// @Summary Leaked Memory
+// @Description Get the progress of the memory leak of Dobby
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.MemoryLeak
+// @Router /control/leak/memory [get]
+func (h *Handler) GetMemoryLeak(c *gin.Context) {
+	d, ok := h.disruptions.find(memoryLeakDisruption)
+	if !ok {
+		report := model.MemoryLeak{Status: leakIdle}
+		report.HeapInUseInMB, report.SysInMB = memoryStats()
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.JSON(http.StatusOK, d.(*memoryLeak).report())
+}
+
+// FreeMemoryLeak godoc
+// @Summary Free Memory Leak
+// @Description Make Dobby free the memory it leaked, like stopping the memory leak disruption
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/leak/memory [delete]
+func (h *Handler) FreeMemoryLeak(c *gin.Context) {
+	if len(h.disruptions.stopKind(memoryLeakDisruption)) == 0 {
+		c.JSON(http.StatusNotFound, model.Error{Error: "no memory is leaked"})
+		return
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("%s leak freed", memoryLeakKind), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
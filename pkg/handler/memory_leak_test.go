@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestMemoryLeak(t *testing.T) {
+	t.Run("should leak at the rate till the ceiling and free it", func(t *testing.T) {
+		leak := newMemoryLeak(model.MemoryLeak{RateInMBPerMinute: 1200, CeilingInMB: 4, Touch: true})
+		assert.Equal(t, 50*time.Millisecond, leak.interval())
+		go leak.run()
+
+		deadline := time.Now().Add(2 * time.Second)
+		for leak.currentStatus() != leakHolding {
+			if time.Now().After(deadline) {
+				t.Fatalf("memory leak is %s, expected %s", leak.currentStatus(), leakHolding)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		report := leak.report()
+		assert.Equal(t, 4, report.LeakedInMB)
+		assert.Equal(t, float64(100), report.ProgressPercent)
+		assert.Equal(t, 0, report.CeilingInSeconds)
+
+		leak.release()
+		assert.Equal(t, leakReleased, leak.currentStatus())
+		assert.Equal(t, 0, leak.report().LeakedInMB)
+	})
+
+	t.Run("should report the time left till the ceiling", func(t *testing.T) {
+		leak := newMemoryLeak(model.MemoryLeak{RateInMBPerMinute: 10, CeilingInMB: 100})
+		report := leak.report()
+		assert.Equal(t, leakLeaking, report.Status)
+		assert.Equal(t, float64(0), report.ProgressPercent)
+		assert.Equal(t, 600, report.CeilingInSeconds)
+	})
+}
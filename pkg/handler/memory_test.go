@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func waitForMemoryStatus(t *testing.T, load *memoryLoad, status string) {
+	deadline := time.Now().Add(2 * time.Second)
+	for load.report().Status != status {
+		if time.Now().After(deadline) {
+			t.Fatalf("memory load is %s, expected %s", load.report().Status, status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMemoryLoad(t *testing.T) {
+	t.Run("should allocate till the target and hold it till released", func(t *testing.T) {
+		load := newMemoryLoad(model.MemoryLoad{TargetInMB: 4, Touch: true})
+		go load.run(func() { t.Error("hold should not expire") })
+
+		waitForMemoryStatus(t, load, memoryHolding)
+		assert.Equal(t, 4, load.report().AllocatedInMB)
+
+		load.release()
+		report := load.report()
+		assert.Equal(t, memoryReleased, report.Status)
+		assert.Equal(t, 0, report.AllocatedInMB)
+	})
+
+	t.Run("should ramp up at the given rate", func(t *testing.T) {
+		load := newMemoryLoad(model.MemoryLoad{TargetInMB: 100, RampInMBPerSecond: 10})
+		go load.run(func() {})
+		defer load.release()
+
+		time.Sleep(300 * time.Millisecond)
+		allocated := load.report().AllocatedInMB
+		assert.True(t, allocated >= 1 && allocated <= 5, "allocated %d MB", allocated)
+		assert.Equal(t, memoryRamping, load.report().Status)
+	})
+
+	t.Run("should expire after the hold duration", func(t *testing.T) {
+		load := newMemoryLoad(model.MemoryLoad{TargetInMB: 1, HoldInSeconds: 1})
+		expired := make(chan struct{})
+		go load.run(func() { close(expired) })
+
+		select {
+		case <-expired:
+		case <-time.After(2 * time.Second):
+			t.Fatal("hold did not expire")
+		}
+	})
+
+	t.Run("should release the current load when a new one starts", func(t *testing.T) {
+		ds := newDisruptions()
+		first := newMemoryLoad(model.MemoryLoad{TargetInMB: 1})
+		ds.replace(memoryDisruption, nil, first)
+		ds.replace(memoryDisruption, nil, newMemoryLoad(model.MemoryLoad{TargetInMB: 1}))
+
+		assert.Equal(t, memoryReleased, first.report().Status)
+		assert.Len(t, ds.stopKind(memoryDisruption), 1)
+		assert.Empty(t, ds.stopKind(memoryDisruption))
+	})
+}
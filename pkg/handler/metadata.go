@@ -19,11 +19,12 @@ import (
 // @Failure 500 {object} model.Error
 // @Router /meta [get]
 func (h *Handler) Meta(c *gin.Context) {
-	if !h.isReady {
+	snapshot := h.state.snapshot()
+	if !h.isHealthy(readinessProbe, snapshot.readiness) {
 		c.JSON(http.StatusServiceUnavailable, model.Error{Error: "application is not ready"})
 		return
 	}
-	if !h.isHealthy {
+	if !h.isHealthy(healthProbe, snapshot.health) {
 		c.JSON(http.StatusInternalServerError, model.Error{Error: "application is not healthy"})
 		return
 	}
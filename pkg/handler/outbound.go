@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const defaultBlackholeTimeout = 30 * time.Second
+
+// outboundRule delays, fails or blackholes the outbound requests to the hosts it matches
+type outboundRule struct {
+	config model.OutboundRule
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+func newOutboundRule(config model.OutboundRule) *outboundRule {
+	return &outboundRule{config: config, random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (o *outboundRule) matches(r *http.Request) bool {
+	if o.config.Host == "" {
+		return true
+	}
+	for _, host := range []string{r.URL.Host, r.URL.Hostname()} {
+		if matched, err := path.Match(o.config.Host, host); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *outboundRule) fails() bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.random.Float64()*100 < o.config.Percentage
+}
+
+func (o *outboundRule) blackholeTimeout() time.Duration {
+	if o.config.TimeoutInSeconds == 0 {
+		return defaultBlackholeTimeout
+	}
+	return time.Duration(o.config.TimeoutInSeconds) * time.Second
+}
+
+func validateOutboundRule(config model.OutboundRule) error {
+	if config.DelayInMilliseconds < 0 || config.TimeoutInSeconds < 0 {
+		return fmt.Errorf("delayInMilliseconds and timeoutInSeconds should not be negative")
+	}
+	if config.Percentage < 0 || config.Percentage > 100 {
+		return fmt.Errorf("percentage should be between 0 and 100")
+	}
+	if config.StatusCode != 0 && (config.StatusCode < 100 || config.StatusCode > 599) {
+		return fmt.Errorf("statusCode should be between 100 and 599")
+	}
+	if config.DelayInMilliseconds == 0 && config.Percentage == 0 && !config.Blackhole {
+		return fmt.Errorf("rule should delay, fail or blackhole the requests")
+	}
+	if _, err := path.Match(config.Host, ""); err != nil {
+		return fmt.Errorf("host %s is not a valid glob", config.Host)
+	}
+	return nil
+}
+
+// outboundClient wraps the client making the outbound requests,
+// so that the requests matching an outbound rule are degraded without touching the upstream
+type outboundClient struct {
+	client httpClient
+	rules  *rules
+}
+
+func (oc *outboundClient) Do(req *http.Request) (*http.Response, error) {
+	r := oc.rules.firstMatching(req)
+	if r == nil {
+		return oc.client.Do(req)
+	}
+	o := r.(*outboundRule)
+	if o.config.Blackhole {
+		oc.wait(req, o.blackholeTimeout())
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("i/o timeout, %s is blackholed", req.URL.Host)}
+	}
+	if !oc.wait(req, time.Duration(o.config.DelayInMilliseconds)*time.Millisecond) {
+		return nil, req.Context().Err()
+	}
+	if !o.fails() {
+		return oc.client.Do(req)
+	}
+	if o.config.StatusCode == 0 {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", o.config.StatusCode, http.StatusText(o.config.StatusCode)),
+		StatusCode: o.config.StatusCode,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+// wait returns false if the request is cancelled before the delay elapses
+func (oc *outboundClient) wait(req *http.Request, delay time.Duration) bool {
+	if delay == 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-req.Context().Done():
+		return false
+	}
+}
+
+// AddOutboundRule godoc
+// @Summary Add Outbound Fault
+// @Description Delay, fail the percentage of, or blackhole the requests Dobby makes to the hosts matching the glob,
+// @Description when calling, proxying and checking its dependencies. Failures are connection errors, or synthetic responses with the status code
+// @Description The earliest added rule matching a request applies
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param body body model.OutboundRule true "'{host: httpbin.org, delayInMilliseconds: 200, percentage: 30, statusCode: 503}'"
+// @Success 201 {object} model.OutboundRule
+// @Failure 400 {object} model.Error
+// @Router /control/outbound [post]
+func (h *Handler) AddOutboundRule(c *gin.Context) {
+	var config model.OutboundRule
+	if err := json.NewDecoder(c.Request.Body).Decode(&config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("error when decoding request: %s", err.Error())})
+		return
+	}
+	if err := validateOutboundRule(config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	id := h.outboundRules.add(func(id int) rule {
+		config.ID = id
+		return newOutboundRule(config)
+	})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("outbound rule %d", id), controlCause(c), c.Request.RemoteAddr)
+	h.disruptions.add(outboundDisruption, map[string]string{"rule": strconv.Itoa(id)}, ruleDisruption{rules: h.outboundRules, id: id})
+	c.JSON(http.StatusCreated, config)
+}
+
+// GetOutboundRules godoc
+// @Summary List Outbound Faults
+// @Description Get the rules degrading the requests Dobby makes
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.OutboundRule
+// @Router /control/outbound [get]
+func (h *Handler) GetOutboundRules(c *gin.Context) {
+	list := h.outboundRules.list()
+	configs := make([]model.OutboundRule, 0, len(list))
+	for _, r := range list {
+		configs = append(configs, r.(*outboundRule).config)
+	}
+	c.JSON(http.StatusOK, configs)
+}
+
+// DeleteOutboundRule godoc
+// @Summary Delete Outbound Fault
+// @Description Stop degrading the requests Dobby makes to the hosts matching a rule
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the rule - E.g. 1"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Router /control/outbound/{id} [delete]
+func (h *Handler) DeleteOutboundRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: "id should be an integer"})
+		return
+	}
+	if !h.outboundRules.remove(id) {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("outbound rule %d is not found", id)})
+		return
+	}
+	h.disruptions.forget(ruleDisruption{rules: h.outboundRules, id: id})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("outbound rule %d removed", id), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/thecasualcoder/dobby/internal/mock/handler"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestOutboundClient(t *testing.T) {
+	newClient := func(t *testing.T, config model.OutboundRule) (*outboundClient, *mock.MockhttpClient) {
+		ctrl := gomock.NewController(t)
+		upstream := mock.NewMockhttpClient(ctrl)
+		rs := newRules()
+		rs.add(func(id int) rule {
+			config.ID = id
+			return newOutboundRule(config)
+		})
+		return &outboundClient{client: upstream, rules: rs}, upstream
+	}
+
+	t.Run("should pass the requests to other hosts through", func(t *testing.T) {
+		client, upstream := newClient(t, model.OutboundRule{Host: "*.example.com", Percentage: 100})
+		request := httptest.NewRequest("GET", "http://httpbin.org:8080/control/get", nil)
+		upstream.EXPECT().Do(request).Return(&http.Response{StatusCode: 200}, nil)
+
+		response, err := client.Do(request)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode)
+	})
+
+	t.Run("should fail the requests with a connection error or the status code", func(t *testing.T) {
+		client, _ := newClient(t, model.OutboundRule{Host: "httpbin.org", Percentage: 100})
+		_, err := client.Do(httptest.NewRequest("GET", "http://httpbin.org:8080/get", nil))
+		assert.EqualError(t, err, "dial tcp: connection refused")
+
+		client, _ = newClient(t, model.OutboundRule{Host: "httpbin.org:8080", Percentage: 100, StatusCode: 503})
+		response, err := client.Do(httptest.NewRequest("GET", "http://httpbin.org:8080/get", nil))
+		assert.NoError(t, err)
+		assert.Equal(t, 503, response.StatusCode)
+		assert.Equal(t, "503 Service Unavailable", response.Status)
+	})
+
+	t.Run("should delay the requests", func(t *testing.T) {
+		client, upstream := newClient(t, model.OutboundRule{DelayInMilliseconds: 100})
+		upstream.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: 200}, nil)
+
+		start := time.Now()
+		_, err := client.Do(httptest.NewRequest("GET", "http://httpbin.org/get", nil))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("should hang the blackholed requests till they are cancelled", func(t *testing.T) {
+		client, _ := newClient(t, model.OutboundRule{Host: "httpbin.org", Blackhole: true})
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.Do(httptest.NewRequest("GET", "http://httpbin.org/get", nil).WithContext(ctx))
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), defaultBlackholeTimeout)
+	})
+
+	t.Run("should not accept invalid rules", func(t *testing.T) {
+		assert.Error(t, validateOutboundRule(model.OutboundRule{Host: "httpbin.org"}))
+		assert.Error(t, validateOutboundRule(model.OutboundRule{Percentage: 120}))
+		assert.Error(t, validateOutboundRule(model.OutboundRule{Percentage: 10, StatusCode: 42}))
+		assert.Error(t, validateOutboundRule(model.OutboundRule{Host: "[", Blackhole: true}))
+		assert.NoError(t, validateOutboundRule(model.OutboundRule{Host: "*.example.com", DelayInMilliseconds: 100}))
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resetProbe makes the probe healthy or unhealthy,
+// stopping it from flapping or being flaky
+func (h *Handler) resetProbe(name string, healthy bool, cause, remoteAddr string) {
+	h.state.updateProbe(name, func(p *probeState) {
+		h.history.recordBool(name, p.healthy, healthy, cause, remoteAddr)
+		p.healthy = healthy
+		p.flap = nil
+		p.flakiness = nil
+	})
+}
+
+func (h *Handler) setProbeHealthy(name string, healthy bool, cause, remoteAddr string) {
+	h.state.updateProbe(name, func(p *probeState) {
+		h.history.recordBool(name, p.healthy, healthy, cause, remoteAddr)
+		p.healthy = healthy
+	})
+}
+
+// setProbeMode changes how the probe behaves, recording the mode and any change of health it causes
+func (h *Handler) setProbeMode(c *gin.Context, name, mode string, update func(p *probeState)) {
+	cause, remoteAddr := controlCause(c), c.Request.RemoteAddr
+	h.history.record(name, "", mode, cause, remoteAddr)
+	h.trackProbes(cause, remoteAddr, func() {
+		h.state.updateProbe(name, update)
+	})
+}
+
+// trackProbes records the changes of health of the probes caused by the change,
+// like the ones caused by flapping, dependencies and checks
+func (h *Handler) trackProbes(cause, remoteAddr string, change func()) {
+	names := []string{healthProbe, readinessProbe}
+	before := make([]bool, len(names))
+	for i, name := range names {
+		before[i] = h.isHealthy(name, h.state.probe(name))
+	}
+	change()
+	for i, name := range names {
+		if after := h.isHealthy(name, h.state.probe(name)); after != before[i] {
+			h.history.recordBool(name, before[i], after, cause, remoteAddr)
+		}
+	}
+}
+
+// recordFlaps records the toggles of the flap till it stops flapping or is replaced
+func (h *Handler) recordFlaps(name string, f *flap) {
+	for {
+		before, _ := f.state(time.Now())
+		timer := time.NewTimer(time.Until(f.nextToggle(time.Now())))
+		<-timer.C
+		p := h.state.probe(name)
+		if p.flap != f {
+			return
+		}
+		after, active := f.state(time.Now())
+		if !active {
+			after = p.healthy
+		}
+		if after != before {
+			h.history.recordBool(name, before, after, flapCause, "")
+		}
+		if !active {
+			return
+		}
+	}
+}
+
+// isHealthy returns whether the probe is healthy,
+// taking its flapping, dependencies and checks into account
+func (h *Handler) isHealthy(name string, p probeState) bool {
+	healthy := p.healthy
+	if flapHealthy, flapping := p.flap.state(time.Now()); flapping {
+		healthy = flapHealthy
+	}
+	return healthy && h.dependencies.healthy(name) && h.checks.passing(name)
+}
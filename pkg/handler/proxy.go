@@ -10,7 +10,7 @@ import (
 // ProxyRoute will route to custom route if the route is found in proxyRequests
 // this will be invoked when no standard routes are found in gin
 func (h *Handler) ProxyRoute(c Context) {
-	proxyConfig := h.proxyRequests.getProxy(c.GetURI().Path, c.GetMethod())
+	proxyConfig := h.state.getProxy(c.GetURI().Path, c.GetMethod())
 	if proxyConfig == nil {
 		c.Status(404)
 		return
@@ -46,11 +46,11 @@ func (h *Handler) AddProxy(c Context) {
 		c.JSON(400, gin.H{"error": fmt.Sprintf("error when decoding request: %s", err.Error())})
 		return
 	}
-	if h.proxyRequests.isPresent(proxyRequest) {
+	if !h.state.addProxy(proxyRequest) {
 		c.JSON(400, gin.H{"error": fmt.Sprintf("proxy configuration for url: %s and method: %s is already added", proxyRequest.Path, proxyRequest.Method)})
 		return
 	}
-	h.proxyRequests = append(h.proxyRequests, proxyRequest)
+	h.history.record(proxySubject, "", proxyRequest.String(), contextCause(c), c.GetRemoteAddr())
 	c.Status(201)
 }
 
@@ -72,8 +72,8 @@ func (h *Handler) DeleteProxy(c Context) {
 		c.JSON(400, gin.H{"error": fmt.Sprintf("error when decoding request: %s", err.Error())})
 		return
 	}
-	if h.proxyRequests.isPresent(proxyRequest) {
-		h.proxyRequests = h.proxyRequests.deleteProxy(proxyRequest.Path, proxyRequest.Method)
+	if h.state.deleteProxy(proxyRequest) {
+		h.history.record(proxySubject, proxyRequest.String(), "", contextCause(c), c.GetRemoteAddr())
 		c.JSON(200, gin.H{"result": "deleted the proxy config successfully"})
 		return
 	}
@@ -86,6 +86,17 @@ type proxyRequest struct {
 	Proxy  proxy  `json:"proxy"`
 }
 
+func (p proxyRequest) String() string {
+	if p.Proxy.URL == "" {
+		return fmt.Sprintf("%s %s", p.Method, p.Path)
+	}
+	return fmt.Sprintf("%s %s -> %s %s", p.Method, p.Path, p.Proxy.Method, p.Proxy.URL)
+}
+
+func contextCause(c Context) string {
+	return fmt.Sprintf("%s %s", c.GetMethod(), c.GetURI().RequestURI())
+}
+
 type proxyRequests []proxyRequest
 
 func (ps proxyRequests) isPresent(requestedProxyRequest proxyRequest) bool {
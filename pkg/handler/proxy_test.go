@@ -30,11 +30,18 @@ func TestHandler_AddProxy(t *testing.T) {
  }
 }`)
 		mockContext.EXPECT().GetRequestBody().Return(io.NopCloser(stringReader))
+		mockContext.EXPECT().GetMethod().Return("POST")
+		mockContext.EXPECT().GetURI().Return(&url.URL{Path: "/proxy"})
+		mockContext.EXPECT().GetRemoteAddr().Return("10.0.0.1:53422")
 		mockContext.EXPECT().Status(201)
 
 		handler.AddProxy(mockContext)
 
-		assert.Len(t, handler.proxyRequests, 1)
+		transitions := handler.history.list()
+		assert.Equal(t, "GET /v1/version -> GET http://dobby2/version", transitions[len(transitions)-1].To)
+		assert.Equal(t, "POST /proxy", transitions[len(transitions)-1].Cause)
+		assert.Equal(t, "10.0.0.1:53422", transitions[len(transitions)-1].RemoteAddr)
+		assert.Len(t, handler.state.snapshot().proxyRequests, 1)
 		expectedProxyRequest := proxyRequest{
 			Path:   "/v1/version",
 			Method: "GET", Proxy: proxy{
@@ -42,12 +49,12 @@ func TestHandler_AddProxy(t *testing.T) {
 				Method: "GET",
 			},
 		}
-		assert.Equal(t, expectedProxyRequest, handler.proxyRequests[0])
+		assert.Equal(t, expectedProxyRequest, handler.state.snapshot().proxyRequests[0])
 	})
 
 	t.Run("should not add the proxy request if the same url and same method is added", func(t *testing.T) {
 		handler := New(true, true, nil)
-		handler.proxyRequests = proxyRequests{proxyRequest{
+		handler.state.proxyRequests = proxyRequests{proxyRequest{
 			Path:   "/v1/version",
 			Method: "GET",
 		}}
@@ -67,12 +74,12 @@ func TestHandler_AddProxy(t *testing.T) {
 
 		handler.AddProxy(mockContext)
 
-		assert.Len(t, handler.proxyRequests, 1)
+		assert.Len(t, handler.state.snapshot().proxyRequests, 1)
 		expectedProxyRequest := proxyRequest{
 			Path:   "/v1/version",
 			Method: "GET",
 		}
-		assert.Equal(t, expectedProxyRequest, handler.proxyRequests[0])
+		assert.Equal(t, expectedProxyRequest, handler.state.snapshot().proxyRequests[0])
 	})
 }
 
@@ -85,7 +92,7 @@ func TestHandler_ProxyRoute(t *testing.T) {
 		expectedURL := "/version"
 		expectedResponse := &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(""))}
 		handler := New(true, true, client)
-		handler.proxyRequests = proxyRequests{{
+		handler.state.proxyRequests = proxyRequests{{
 			Path:   "/v1/version",
 			Method: "GET",
 			Proxy: proxy{
@@ -120,7 +127,7 @@ func TestHandler_ProxyRoute(t *testing.T) {
 		client := mock.NewMockhttpClient(ctrl)
 
 		handler := New(true, true, client)
-		handler.proxyRequests = proxyRequests{{
+		handler.state.proxyRequests = proxyRequests{{
 			Path:   "/v1/version",
 			Method: "GET",
 			Proxy: proxy{
@@ -144,7 +151,7 @@ func TestHandler_ProxyRoute(t *testing.T) {
 		client := mock.NewMockhttpClient(ctrl)
 
 		handler := New(true, true, client)
-		handler.proxyRequests = proxyRequests{{
+		handler.state.proxyRequests = proxyRequests{{
 			Path:   "/v1/version",
 			Method: "GET",
 			Proxy: proxy{
@@ -169,7 +176,7 @@ func TestHandler_DeleteProxy(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockContext := mock.NewMockContext(ctrl)
-		handler.proxyRequests = proxyRequests{proxyRequest{
+		handler.state.proxyRequests = proxyRequests{proxyRequest{
 			Path:   "/v1/version",
 			Method: "GET",
 		}}
@@ -179,13 +186,16 @@ func TestHandler_DeleteProxy(t *testing.T) {
  "method": "GET"
 }`)
 		mockContext.EXPECT().GetRequestBody().Return(io.NopCloser(stringReader))
+		mockContext.EXPECT().GetMethod().Return("DELETE")
+		mockContext.EXPECT().GetURI().Return(&url.URL{Path: "/proxy"})
+		mockContext.EXPECT().GetRemoteAddr().Return("10.0.0.1:53422")
 		mockContext.EXPECT().JSON(200, gomock.Any()).Do(func(_ int, data interface{}) {
 			assert.EqualValues(t, "deleted the proxy config successfully", data.(gin.H)["result"])
 		})
 
 		handler.DeleteProxy(mockContext)
 
-		assert.Len(t, handler.proxyRequests, 0)
+		assert.Len(t, handler.state.snapshot().proxyRequests, 0)
 	})
 
 	t.Run("should not delete the proxy request if not present", func(t *testing.T) {
@@ -193,7 +203,7 @@ func TestHandler_DeleteProxy(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 		mockContext := mock.NewMockContext(ctrl)
-		handler.proxyRequests = proxyRequests{proxyRequest{
+		handler.state.proxyRequests = proxyRequests{proxyRequest{
 			Path:   "/v1/version",
 			Method: "GET",
 		}}
@@ -209,6 +219,6 @@ func TestHandler_DeleteProxy(t *testing.T) {
 
 		handler.DeleteProxy(mockContext)
 
-		assert.Len(t, handler.proxyRequests, 1)
+		assert.Len(t, handler.state.snapshot().proxyRequests, 1)
 	})
 }
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/thecasualcoder/dobby/pkg/model"
 	"net/http"
@@ -9,18 +10,29 @@ import (
 // Ready return the dobby health status
 // @Summary Dobby Ready
 // @Description Get Dobby's readiness
+// @Description Renders application/health+json when asked for in the Accept header
 // @Tags Status
 // @Accept json
 // @Produce json
+// @Produce application/health+json
 // @Success 200 {object} model.Ready
 // @Failure 503 {object} model.Ready
 // @Router /ready [get]
 func (h *Handler) Ready(c *gin.Context) {
+	if !h.state.probe(readinessProbe).latency.wait(c.Request.Context()) {
+		return
+	}
+	probe := h.state.probe(readinessProbe)
+	ready := !probe.flakiness.fails() && h.isHealthy(readinessProbe, probe)
+	if h.wantsHealthJSON(c) {
+		h.renderHealthJSON(c, readinessProbe, ready, http.StatusServiceUnavailable, "application is not ready")
+		return
+	}
 	statusCode := http.StatusOK
-	if !h.isReady {
+	if !ready {
 		statusCode = http.StatusServiceUnavailable
 	}
-	c.JSON(statusCode, model.Ready{Ready: h.isReady})
+	c.JSON(statusCode, model.Ready{Ready: ready, Dependencies: h.dependencies.checks(readinessProbe)})
 }
 
 // MakeReadyPerfect godoc
@@ -32,7 +44,8 @@ func (h *Handler) Ready(c *gin.Context) {
 // @Success 200 {object} model.ControlSuccess
 // @Router /control/ready/perfect [put]
 func (h *Handler) MakeReadyPerfect(c *gin.Context) {
-	h.isReady = true
+	h.timers.cancelSubject(readinessProbe)
+	h.resetProbe(readinessProbe, true, controlCause(c), c.Request.RemoteAddr)
 	c.JSON(200, model.ControlSuccess{Status: "success"})
 }
 
@@ -46,9 +59,94 @@ func (h *Handler) MakeReadyPerfect(c *gin.Context) {
 // @Param resetInSeconds query int false "Recover readiness after sometime (seconds) - E.g. 2"
 // @Router /control/ready/sick [put]
 func (h *Handler) MakeReadySick(c *gin.Context) {
-	h.isReady = false
-	setupResetFunction(c, func() {
-		h.isReady = true
+	h.timers.cancelSubject(readinessProbe)
+	h.resetProbe(readinessProbe, false, controlCause(c), c.Request.RemoteAddr)
+	remoteAddr := c.Request.RemoteAddr
+	h.scheduleReset(c, readinessProbe, func(cause string) {
+		h.setProbeHealthy(readinessProbe, true, cause, remoteAddr)
+	})
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// MakeReadyFlap godoc
+// @Summary Make Readiness Flap
+// @Description Make Dobby alternate between ready and unready
+// @Description Dobby is ready for the first dutyCycle fraction of every period
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param periodInSeconds query int true "Length of one ready and unready cycle (seconds) - E.g. 10"
+// @Param dutyCycle query number false "Fraction of the period to be ready, defaults to 0.5 - E.g. 0.8"
+// @Param durationInSeconds query int false "Stop flapping after sometime (seconds) - E.g. 60"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/ready/flap [put]
+func (h *Handler) MakeReadyFlap(c *gin.Context) {
+	f, err := newFlap(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.setProbeMode(c, readinessProbe, fmt.Sprintf("flapping every %s", f.period), func(p *probeState) {
+		p.flap = f
 	})
+	go h.recordFlaps(readinessProbe, f)
 	c.JSON(200, model.ControlSuccess{Status: "success"})
 }
+
+// MakeReadyFlaky godoc
+// @Summary Make Readiness Flaky
+// @Description Make every request to Dobby's readiness fail with the given probability
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param failureRate query number true "Probability of a readiness request to fail, 0 disables it - E.g. 0.3"
+// @Param seed query int false "Seed to make the failures reproducible - E.g. 42"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/ready/flaky [put]
+func (h *Handler) MakeReadyFlaky(c *gin.Context) {
+	f, err := flakinessFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.setProbeMode(c, readinessProbe, f.mode(), func(p *probeState) {
+		p.flakiness = f
+	})
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// MakeReadySlow godoc
+// @Summary Make Readiness Slow
+// @Description Make Dobby respond slowly to readiness requests
+// @Description Delays by a fixed delay, a random delay between minDelay and maxDelay, or hangs till the client disconnects
+// @Description Calling without any of them makes readiness respond promptly again
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param delay query int false "Fixed delay (milliseconds) - E.g. 1000"
+// @Param minDelay query int false "Minimum delay (milliseconds) - E.g. 500"
+// @Param maxDelay query int false "Maximum delay (milliseconds) - E.g. 3000"
+// @Param hang query bool false "Hang till the client disconnects - E.g. true"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/ready/slow [put]
+func (h *Handler) MakeReadySlow(c *gin.Context) {
+	l, err := latencyFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	h.setProbeMode(c, readinessProbe, l.mode(), func(p *probeState) {
+		p.latency = l
+	})
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// MarkUnready makes dobby unready for the given cause, cancelling any pending recovery,
+// this is used to drain the traffic before shutting down
+func (h *Handler) MarkUnready(cause string) {
+	h.timers.cancelSubject(readinessProbe)
+	h.resetProbe(readinessProbe, false, cause, "")
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ruleActive  = "active"
+	ruleRemoved = "removed"
+)
+
+// rule applies to the requests it matches
+type rule interface {
+	matches(r *http.Request) bool
+}
+
+// matchesRequest tells whether the request matches the path glob and the method, an empty one matches any
+func matchesRequest(pathPattern, method string, r *http.Request) bool {
+	if method != "" && !strings.EqualFold(method, r.Method) {
+		return false
+	}
+	if pathPattern == "" {
+		return true
+	}
+	matched, err := path.Match(pathPattern, r.URL.Path)
+	return err == nil && matched
+}
+
+func validatePathPattern(pathPattern string) error {
+	if _, err := path.Match(pathPattern, ""); err != nil {
+		return fmt.Errorf("path %s is not a valid glob", pathPattern)
+	}
+	return nil
+}
+
+// rules holds the rules added at runtime by their id,
+// the control routes are never matched by first so that the rules can always be removed
+type rules struct {
+	mutex  sync.RWMutex
+	lastID int
+	byID   map[int]rule
+}
+
+func newRules() *rules {
+	return &rules{byID: make(map[int]rule)}
+}
+
+func (rs *rules) add(create func(id int) rule) int {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	rs.lastID++
+	rs.byID[rs.lastID] = create(rs.lastID)
+	return rs.lastID
+}
+
+func (rs *rules) remove(id int) bool {
+	rs.mutex.Lock()
+	defer rs.mutex.Unlock()
+	if _, ok := rs.byID[id]; !ok {
+		return false
+	}
+	delete(rs.byID, id)
+	return true
+}
+
+func (rs *rules) has(id int) bool {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	_, ok := rs.byID[id]
+	return ok
+}
+
+func (rs *rules) ids() []int {
+	ids := make([]int, 0, len(rs.byID))
+	for id := range rs.byID {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// list returns the rules in the order in which they were added
+func (rs *rules) list() []rule {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	list := make([]rule, 0, len(rs.byID))
+	for _, id := range rs.ids() {
+		list = append(list, rs.byID[id])
+	}
+	return list
+}
+
+// first returns the earliest added rule which matches the request
+func (rs *rules) first(r *http.Request) rule {
+	if strings.HasPrefix(r.URL.Path, "/control/") {
+		return nil
+	}
+	return rs.firstMatching(r)
+}
+
+// firstMatching returns the earliest added rule which matches the request, even on the control routes
+func (rs *rules) firstMatching(r *http.Request) rule {
+	rs.mutex.RLock()
+	defer rs.mutex.RUnlock()
+	if len(rs.byID) == 0 {
+		return nil
+	}
+	for _, id := range rs.ids() {
+		if rs.byID[id].matches(r) {
+			return rs.byID[id]
+		}
+	}
+	return nil
+}
+
+// ruleDisruption lets a rule be listed and stopped as a disruption
+type ruleDisruption struct {
+	rules *rules
+	id    int
+}
+
+func (d ruleDisruption) currentStatus() string {
+	if d.rules.has(d.id) {
+		return ruleActive
+	}
+	return ruleRemoved
+}
+
+func (d ruleDisruption) stopped() bool {
+	return !d.rules.has(d.id)
+}
+
+func (d ruleDisruption) release() {
+	d.rules.remove(d.id)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+// Shutdown godoc
+// @Summary Shutdown
+// @Description Make Dobby shut down gracefully and exit with the given code
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param exitCode query int false "Exit code, defaults to 0 - E.g. 3"
+// @Param delayInSeconds query int false "Shut down after sometime (seconds) - E.g. 5"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Router /control/shutdown [put]
+func (h *Handler) Shutdown(c *gin.Context) {
+	exitCode := 0
+	if exitCodeStr := c.Query("exitCode"); exitCodeStr != "" {
+		code, err := strconv.Atoi(exitCodeStr)
+		if err != nil || code < 0 || code > 255 {
+			c.JSON(http.StatusBadRequest, model.Error{Error: "exitCode should be between 0 and 255"})
+			return
+		}
+		exitCode = code
+	}
+	delay := 0
+	if delayStr := c.Query("delayInSeconds"); delayStr != "" {
+		seconds, err := strconv.Atoi(delayStr)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, model.Error{Error: "delayInSeconds should be a non negative integer"})
+			return
+		}
+		delay = seconds
+	}
+	h.history.record(disruptionSubject, "", fmt.Sprintf("shutdown with exit code %d", exitCode), controlCause(c), c.Request.RemoteAddr)
+	go func() {
+		time.Sleep(time.Duration(delay) * time.Second)
+		select {
+		case h.shutdownRequests <- exitCode:
+		default:
+		}
+	}()
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
+
+// ShutdownRequests returns the exit codes asked for through /control/shutdown
+func (h *Handler) ShutdownRequests() <-chan int {
+	return h.shutdownRequests
+}
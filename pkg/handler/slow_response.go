@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+const (
+	throttleMode  = "throttle"
+	slowlorisMode = "slowloris"
+
+	// throttledChunksPerSecond is the number of chunks in which a throttled body is written every second
+	throttledChunksPerSecond = 10
+	defaultSlowBodySize      = 1024
+	maxSlowBodySize          = 10 * 1024 * 1024
+	maxSlowlorisInterval     = 3600
+)
+
+// chunking is the size of the chunks in which the body is written, and the pause before each of them
+func chunking(config model.SlowResponseRule) (int, time.Duration) {
+	if config.Mode == slowlorisMode {
+		return 1, time.Duration(config.IntervalInSeconds) * time.Second
+	}
+	size := config.BytesPerSecond / throttledChunksPerSecond
+	if size < 1 {
+		size = 1
+	}
+	// the pause is computed in floating point, as size * time.Second overflows for large rates
+	return size, time.Duration(float64(size) / float64(config.BytesPerSecond) * float64(time.Second))
+}
+
+func validateSlowResponse(config model.SlowResponseRule) error {
+	switch config.Mode {
+	case throttleMode:
+		if config.BytesPerSecond <= 0 {
+			return fmt.Errorf("bytesPerSecond should be a positive integer")
+		}
+	case slowlorisMode:
+		if config.IntervalInSeconds <= 0 || config.IntervalInSeconds > maxSlowlorisInterval {
+			return fmt.Errorf("intervalInSeconds should be between 1 and %d", maxSlowlorisInterval)
+		}
+	default:
+		return fmt.Errorf("mode should be one of %s, %s", throttleMode, slowlorisMode)
+	}
+	return validatePathPattern(config.Path)
+}
+
+// slowWriter writes the headers right away and then the body in chunks, pausing before each of them,
+// till the body is written or the client disconnects
+type slowWriter struct {
+	gin.ResponseWriter
+	ctx   context.Context
+	chunk int
+	pause time.Duration
+}
+
+func newSlowWriter(w gin.ResponseWriter, ctx context.Context, config model.SlowResponseRule) *slowWriter {
+	chunk, pause := chunking(config)
+	return &slowWriter{ResponseWriter: w, ctx: ctx, chunk: chunk, pause: pause}
+}
+
+func (w *slowWriter) Write(data []byte) (int, error) {
+	if !w.Written() {
+		w.WriteHeaderNow()
+		w.Flush()
+	}
+	written := 0
+	for written < len(data) {
+		timer := time.NewTimer(w.pause)
+		select {
+		case <-timer.C:
+		case <-w.ctx.Done():
+			timer.Stop()
+			return written, w.ctx.Err()
+		}
+		end := written + w.chunk
+		if end > len(data) {
+			end = len(data)
+		}
+		n, err := w.ResponseWriter.Write(data[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		w.Flush()
+	}
+	return written, nil
+}
+
+func (w *slowWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// slowResponseRule slows down the responses of the requests it matches
+type slowResponseRule struct {
+	config model.SlowResponseRule
+}
+
+func (s *slowResponseRule) matches(r *http.Request) bool {
+	return matchesRequest(s.config.Path, s.config.Method, r)
+}
+
+// SlowDownResponses is the middleware which slows down the responses of the requests matching a slow response rule
+func (h *Handler) SlowDownResponses(c *gin.Context) {
+	r := h.slowResponseRules.first(c.Request)
+	if r == nil {
+		return
+	}
+	c.Writer = newSlowWriter(c.Writer, c.Request.Context(), r.(*slowResponseRule).config)
+}
+
+func slowResponseFromQuery(c *gin.Context) (model.SlowResponseRule, int, error) {
+	config := model.SlowResponseRule{Mode: c.Param("mode")}
+	nonNegative := func(name string, defaultValue int) (int, error) {
+		value := c.Query(name)
+		if value == "" {
+			return defaultValue, nil
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 {
+			return 0, fmt.Errorf("%s should be a non negative integer", name)
+		}
+		return i, nil
+	}
+	var err error
+	if config.BytesPerSecond, err = nonNegative("bytesPerSecond", 0); err != nil {
+		return config, 0, err
+	}
+	if config.IntervalInSeconds, err = nonNegative("intervalInSeconds", 0); err != nil {
+		return config, 0, err
+	}
+	size, err := nonNegative("sizeInBytes", defaultSlowBodySize)
+	if err != nil {
+		return config, 0, err
+	}
+	if size > maxSlowBodySize {
+		return config, 0, fmt.Errorf("sizeInBytes should be at most %d", maxSlowBodySize)
+	}
+	return config, size, validateSlowResponse(config)
+}
+
+// SlowResponse godoc
+// @Summary Slow Response
+// @Description Ask Dobby to respond with a body of the given size slowly
+// @Description throttle writes it at bytesPerSecond, slowloris writes the headers and then a byte of it every intervalInSeconds
+// @Tags Status
+// @Accept json
+// @Produce plain
+// @Failure 400 {object} model.Error
+// @Param mode path string true "Mode - E.g. throttle"
+// @Param bytesPerSecond query int false "Bytes written every second when throttling - E.g. 100"
+// @Param intervalInSeconds query int false "Interval between the bytes of slowloris, at most 3600 - E.g. 5"
+// @Param sizeInBytes query int false "Size of the body, defaults to 1024 and at most 10485760 - E.g. 500"
+// @Router /slow/{mode} [get]
+func (h *Handler) SlowResponse(c *gin.Context) {
+	config, size, err := slowResponseFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	c.Writer = newSlowWriter(c.Writer, c.Request.Context(), config)
+	c.Header("Content-Type", "text/plain")
+	c.Header("Content-Length", strconv.Itoa(size))
+	c.Status(http.StatusOK)
+	_ = writeBody(c.Writer, size)
+}
+
+// AddSlowResponseRule godoc
+// @Summary Add Slow Response
+// @Description Slow down the responses of the requests matching the path glob and the method
+// @Description The earliest added rule matching a request applies, the control routes are never slowed down
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param body body model.SlowResponseRule true "'{path: /proxy/*, mode: throttle, bytesPerSecond: 100}'"
+// @Success 201 {object} model.SlowResponseRule
+// @Failure 400 {object} model.Error
+// @Router /control/slow [post]
+func (h *Handler) AddSlowResponseRule(c *gin.Context) {
+	var config model.SlowResponseRule
+	if err := json.NewDecoder(c.Request.Body).Decode(&config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: fmt.Sprintf("error when decoding request: %s", err.Error())})
+		return
+	}
+	if err := validateSlowResponse(config); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: err.Error()})
+		return
+	}
+	id := h.slowResponseRules.add(func(id int) rule {
+		config.ID = id
+		return &slowResponseRule{config: config}
+	})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("slow response rule %d", id), controlCause(c), c.Request.RemoteAddr)
+	h.disruptions.add(slowResponseDisruption, map[string]string{"rule": strconv.Itoa(id)}, ruleDisruption{rules: h.slowResponseRules, id: id})
+	c.JSON(http.StatusCreated, config)
+}
+
+// GetSlowResponseRules godoc
+// @Summary List Slow Responses
+// @Description Get the rules slowing down the responses
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.SlowResponseRule
+// @Router /control/slow [get]
+func (h *Handler) GetSlowResponseRules(c *gin.Context) {
+	list := h.slowResponseRules.list()
+	configs := make([]model.SlowResponseRule, 0, len(list))
+	for _, r := range list {
+		configs = append(configs, r.(*slowResponseRule).config)
+	}
+	c.JSON(http.StatusOK, configs)
+}
+
+// DeleteSlowResponseRule godoc
+// @Summary Delete Slow Response
+// @Description Stop slowing down the responses of the requests matching a rule
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the rule - E.g. 1"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Router /control/slow/{id} [delete]
+func (h *Handler) DeleteSlowResponseRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{Error: "id should be an integer"})
+		return
+	}
+	if !h.slowResponseRules.remove(id) {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("slow response rule %d is not found", id)})
+		return
+	}
+	h.disruptions.forget(ruleDisruption{rules: h.slowResponseRules, id: id})
+	h.history.record(disruptionSubject, "", fmt.Sprintf("slow response rule %d removed", id), controlCause(c), c.Request.RemoteAddr)
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+func TestSlowResponse(t *testing.T) {
+	t.Run("should write the body in chunks at the rate", func(t *testing.T) {
+		chunk, pause := chunking(model.SlowResponseRule{Mode: throttleMode, BytesPerSecond: 1000})
+		assert.Equal(t, 100, chunk)
+		assert.Equal(t, 100*time.Millisecond, pause)
+
+		chunk, pause = chunking(model.SlowResponseRule{Mode: throttleMode, BytesPerSecond: 4})
+		assert.Equal(t, 1, chunk)
+		assert.Equal(t, 250*time.Millisecond, pause)
+
+		chunk, pause = chunking(model.SlowResponseRule{Mode: throttleMode, BytesPerSecond: 100000000000})
+		assert.Equal(t, 10000000000, chunk)
+		assert.Equal(t, 100*time.Millisecond, pause)
+
+		chunk, pause = chunking(model.SlowResponseRule{Mode: slowlorisMode, IntervalInSeconds: 5})
+		assert.Equal(t, 1, chunk)
+		assert.Equal(t, 5*time.Second, pause)
+	})
+
+	t.Run("should not accept invalid rules", func(t *testing.T) {
+		assert.Error(t, validateSlowResponse(model.SlowResponseRule{Mode: "crawl"}))
+		assert.Error(t, validateSlowResponse(model.SlowResponseRule{Mode: throttleMode}))
+		assert.Error(t, validateSlowResponse(model.SlowResponseRule{Mode: slowlorisMode, BytesPerSecond: 10}))
+		assert.Error(t, validateSlowResponse(model.SlowResponseRule{Mode: slowlorisMode, IntervalInSeconds: 3601}))
+		assert.Error(t, validateSlowResponse(model.SlowResponseRule{Mode: throttleMode, BytesPerSecond: 10, Path: "[/"}))
+		assert.NoError(t, validateSlowResponse(model.SlowResponseRule{Mode: slowlorisMode, IntervalInSeconds: 1, Path: "/proxy/*"}))
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+// Startup return the dobby startup status
+// @Summary Dobby Startup
+// @Description Get Dobby's startup status
+// @Description Fails till the boot is complete, even though the server is already listening
+// @Tags Status
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.Startup
+// @Failure 503 {object} model.Startup
+// @Router /startup [get]
+func (h *Handler) Startup(c *gin.Context) {
+	started := h.state.startupState().isStarted(time.Now())
+	statusCode := http.StatusOK
+	if !started {
+		statusCode = http.StatusServiceUnavailable
+	}
+	c.JSON(statusCode, model.Startup{Started: started})
+}
+
+// MakeStartupPerfect godoc
+// @Summary Make Started
+// @Description Make Dobby finish its startup, skipping any pending boot
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ControlSuccess
+// @Router /control/startup/perfect [put]
+func (h *Handler) MakeStartupPerfect(c *gin.Context) {
+	h.timers.cancelSubject(startupSubject)
+	h.setStarted(true, controlCause(c), c.Request.RemoteAddr)
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// MakeStartupSick godoc
+// @Summary Make Not Started
+// @Description Make Dobby fail its startup
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param resetInSeconds query int false "Recover startup after sometime (seconds) - E.g. 2"
+// @Success 200 {object} model.ControlSuccess
+// @Router /control/startup/sick [put]
+func (h *Handler) MakeStartupSick(c *gin.Context) {
+	h.timers.cancelSubject(startupSubject)
+	h.setStarted(false, controlCause(c), c.Request.RemoteAddr)
+	remoteAddr := c.Request.RemoteAddr
+	h.scheduleReset(c, startupSubject, func(cause string) {
+		h.setStarted(true, cause, remoteAddr)
+	})
+	c.JSON(200, model.ControlSuccess{Status: "success"})
+}
+
+// SetStartup sets the initial startup state of dobby
+// startup keeps failing for bootDuration even when initialStartup is true,
+// which simulates an application that takes time to boot
+func (h *Handler) SetStartup(initialStartup bool, bootDuration time.Duration) {
+	h.state.updateStartup(func(startup *startupState) {
+		startup.started = initialStartup
+		startup.bootCompletesAt = time.Now().Add(bootDuration)
+	})
+	h.history.record(startupSubject, "", strconv.FormatBool(initialStartup), initialCause, "")
+}
+
+// setStarted sets the startup state, skipping any pending boot
+func (h *Handler) setStarted(started bool, cause, remoteAddr string) {
+	h.state.updateStartup(func(startup *startupState) {
+		h.history.recordBool(startupSubject, startup.started, started, cause, remoteAddr)
+		startup.started = started
+		startup.bootCompletesAt = time.Time{}
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"sync"
+	"time"
+)
+
+// probeState is the state of a probe which can be controlled
+type probeState struct {
+	healthy   bool
+	flap      *flap
+	flakiness *flakiness
+	latency   *latency
+}
+
+// startupState is the state of the startup probe
+type startupState struct {
+	started         bool
+	bootCompletesAt time.Time
+}
+
+func (s startupState) isStarted(now time.Time) bool {
+	return s.started && !now.Before(s.bootCompletesAt)
+}
+
+// stateSnapshot is a consistent copy of the state at a point in time
+type stateSnapshot struct {
+	health        probeState
+	readiness     probeState
+	startup       startupState
+	proxyRequests proxyRequests
+}
+
+// state is the concurrency safe store of dobby's mutable state,
+// shared by the gin handlers and the timers which run in their own goroutines
+type state struct {
+	mutex         sync.RWMutex
+	probes        map[string]*probeState
+	startup       startupState
+	proxyRequests proxyRequests
+}
+
+func newState(initialHealth, initialReadiness bool) *state {
+	return &state{
+		probes: map[string]*probeState{
+			healthProbe:    {healthy: initialHealth},
+			readinessProbe: {healthy: initialReadiness},
+		},
+		startup:       startupState{started: true},
+		proxyRequests: make(proxyRequests, 0),
+	}
+}
+
+func (s *state) snapshot() stateSnapshot {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return stateSnapshot{
+		health:        *s.probes[healthProbe],
+		readiness:     *s.probes[readinessProbe],
+		startup:       s.startup,
+		proxyRequests: append(make(proxyRequests, 0, len(s.proxyRequests)), s.proxyRequests...),
+	}
+}
+
+func (s *state) probe(name string) probeState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return *s.probes[name]
+}
+
+// updateProbe applies update to the probe atomically
+func (s *state) updateProbe(name string, update func(p *probeState)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	update(s.probes[name])
+}
+
+func (s *state) startupState() startupState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.startup
+}
+
+// updateStartup applies update to the startup atomically
+func (s *state) updateStartup(update func(startup *startupState)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	update(&s.startup)
+}
+
+func (s *state) getProxy(path, method string) *proxy {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.proxyRequests.getProxy(path, method)
+}
+
+// addProxy adds the proxy request unless one with the same path and method is present
+func (s *state) addProxy(request proxyRequest) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.proxyRequests.isPresent(request) {
+		return false
+	}
+	s.proxyRequests = append(s.proxyRequests[:len(s.proxyRequests):len(s.proxyRequests)], request)
+	return true
+}
+
+// deleteProxy deletes the proxy request with the same path and method, if present
+func (s *state) deleteProxy(request proxyRequest) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.proxyRequests.isPresent(request) {
+		return false
+	}
+	s.proxyRequests = s.proxyRequests.deleteProxy(request.Path, request.Method)
+	return true
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	t.Run("should add and delete proxies concurrently", func(t *testing.T) {
+		s := newState(true, true)
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				request := proxyRequest{Path: fmt.Sprintf("/v%d", i), Method: "GET"}
+				assert.True(t, s.addProxy(request))
+				assert.False(t, s.addProxy(request))
+				_ = s.snapshot()
+				assert.NotNil(t, s.getProxy(request.Path, request.Method))
+				if i%2 == 0 {
+					assert.True(t, s.deleteProxy(request))
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Len(t, s.snapshot().proxyRequests, 25)
+	})
+
+	t.Run("should return snapshots which are not changed by later updates", func(t *testing.T) {
+		s := newState(true, true)
+		s.addProxy(proxyRequest{Path: "/v1", Method: "GET"})
+		snapshot := s.snapshot()
+
+		s.updateProbe(healthProbe, func(p *probeState) {
+			p.healthy = false
+		})
+		s.addProxy(proxyRequest{Path: "/v2", Method: "GET"})
+
+		assert.True(t, snapshot.health.healthy)
+		assert.Len(t, snapshot.proxyRequests, 1)
+		assert.False(t, s.probe(healthProbe).healthy)
+	})
+}
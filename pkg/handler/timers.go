@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thecasualcoder/dobby/pkg/model"
+)
+
+type pendingTimer struct {
+	model.Timer
+	timer *time.Timer
+}
+
+// timers keeps track of the pending recoveries, so that they can be listed,
+// cancelled or superseded by a newer state change of the same subject
+type timers struct {
+	mutex   sync.Mutex
+	lastID  int
+	pending map[int]*pendingTimer
+}
+
+func newTimers() *timers {
+	return &timers{pending: make(map[int]*pendingTimer)}
+}
+
+// schedule runs action after the given duration, cancelling any pending timer of the subject
+// The action runs under the mutex, so that a newer state change of the subject cancelling it
+// either happens before the action, or after it and wins. Actions should not call back into timers
+func (ts *timers) schedule(subject, description string, after time.Duration, action func(id int)) int {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	ts.cancelSubjectLocked(subject)
+	ts.lastID++
+	id := ts.lastID
+	pending := &pendingTimer{Timer: model.Timer{ID: id, Subject: subject, Action: description, FiresAt: time.Now().Add(after)}}
+	pending.timer = time.AfterFunc(after, func() {
+		ts.fire(id, action)
+	})
+	ts.pending[id] = pending
+	return id
+}
+
+// fire removes the timer and runs its action, unless the timer was already cancelled
+func (ts *timers) fire(id int, action func(id int)) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	if _, ok := ts.pending[id]; !ok {
+		return
+	}
+	delete(ts.pending, id)
+	action(id)
+}
+
+func (ts *timers) cancel(id int) bool {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	pending, ok := ts.pending[id]
+	if !ok {
+		return false
+	}
+	pending.timer.Stop()
+	delete(ts.pending, id)
+	return true
+}
+
+func (ts *timers) cancelSubject(subject string) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	ts.cancelSubjectLocked(subject)
+}
+
+func (ts *timers) cancelSubjectLocked(subject string) {
+	for id, pending := range ts.pending {
+		if pending.Subject == subject {
+			pending.timer.Stop()
+			delete(ts.pending, id)
+		}
+	}
+}
+
+func (ts *timers) list() []model.Timer {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	now := time.Now()
+	result := make([]model.Timer, 0, len(ts.pending))
+	for _, pending := range ts.pending {
+		timer := pending.Timer
+		timer.RemainingSeconds = timer.FiresAt.Sub(now).Seconds()
+		result = append(result, timer)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// scheduleReset schedules a recovery of the subject when resetInSeconds query param is given,
+// superseding any pending recovery of the same subject
+func (h *Handler) scheduleReset(c *gin.Context, subject string, reset func(cause string)) {
+	resetInSeconds, err := strconv.Atoi(c.Query("resetInSeconds"))
+	if err != nil || resetInSeconds == 0 {
+		return
+	}
+	h.timers.schedule(subject, fmt.Sprintf("reset %s to true", subject), time.Second*time.Duration(resetInSeconds), func(id int) {
+		reset(fmt.Sprintf("%s %d", resetCause, id))
+	})
+}
+
+// GetTimers godoc
+// @Summary Pending Timers
+// @Description Get the pending recoveries scheduled through resetInSeconds
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Timer
+// @Router /control/timers [get]
+func (h *Handler) GetTimers(c *gin.Context) {
+	c.JSON(http.StatusOK, h.timers.list())
+}
+
+// CancelTimer godoc
+// @Summary Cancel Timer
+// @Description Cancel a pending recovery
+// @Tags Control
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the timer - E.g. 1"
+// @Success 200 {object} model.ControlSuccess
+// @Failure 404 {object} model.Error
+// @Router /control/timers/{id} [delete]
+func (h *Handler) CancelTimer(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || !h.timers.cancel(id) {
+		c.JSON(http.StatusNotFound, model.Error{Error: fmt.Sprintf("timer %s is not found", c.Param("id"))})
+		return
+	}
+	c.JSON(http.StatusOK, model.ControlSuccess{Status: "success"})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimers(t *testing.T) {
+	t.Run("should not run the action of a cancelled timer", func(t *testing.T) {
+		ts := newTimers()
+		fired := make(chan struct{})
+		ts.schedule(healthProbe, "reset health to true", 20*time.Millisecond, func(int) { close(fired) })
+		ts.cancelSubject(healthProbe)
+
+		select {
+		case <-fired:
+			t.Fatal("cancelled timer fired")
+		case <-time.After(100 * time.Millisecond):
+		}
+		assert.Empty(t, ts.list())
+	})
+
+	t.Run("should let a newer state change wait for a firing action", func(t *testing.T) {
+		ts := newTimers()
+		var mutex sync.Mutex
+		var changes []string
+		change := func(name string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			changes = append(changes, name)
+		}
+		started, proceed, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		ts.schedule(healthProbe, "reset health to true", time.Millisecond, func(int) {
+			close(started)
+			<-proceed
+			change("reset")
+		})
+		<-started
+
+		go func() {
+			ts.cancelSubject(healthProbe)
+			change("sick")
+			close(done)
+		}()
+		select {
+		case <-done:
+			t.Fatal("newer state change did not wait for the firing action")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(proceed)
+		<-done
+		assert.Equal(t, []string{"reset", "sick"}, changes)
+	})
+}
@@ -19,19 +19,22 @@ import (
 // @Failure 500 {object} model.Error
 // @Router /version [get]
 func (h *Handler) Version(c *gin.Context) {
-	if !h.isReady {
+	snapshot := h.state.snapshot()
+	if !h.isHealthy(readinessProbe, snapshot.readiness) {
 		c.JSON(http.StatusServiceUnavailable, model.Error{Error: "application is not ready"})
 		return
 	}
-	if !h.isHealthy {
+	if !h.isHealthy(healthProbe, snapshot.health) {
 		c.JSON(http.StatusInternalServerError, model.Error{Error: "application is not healthy"})
 		return
 	}
 
-	envVersion := os.Getenv("VERSION")
-	version := config.BuildVersion()
-	if envVersion != "" {
-		version = envVersion
+	c.JSON(200, model.Version{Version: appVersion()})
+}
+
+func appVersion() string {
+	if envVersion := os.Getenv("VERSION"); envVersion != "" {
+		return envVersion
 	}
-	c.JSON(200, model.Version{Version: version})
+	return config.BuildVersion()
 }
@@ -0,0 +1,12 @@
+package model
+
+// CPULoad model
+type CPULoad struct {
+	Status             string `json:"status" example:"running"`
+	Cores              int    `json:"cores,omitempty" example:"2"`
+	UtilisationPercent int    `json:"utilisationPercent,omitempty" example:"60"`
+	DurationInSeconds  int    `json:"durationInSeconds,omitempty" example:"300"`
+	// AchievedPercent is the busy time of the cores divided by the time for which they ran
+	AchievedPercent float64 `json:"achievedPercent" example:"59.8"`
+	NumCPU          int     `json:"numCPU" example:"4"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Dependency model
+type Dependency struct {
+	Name string `json:"name" example:"postgres"`
+	// URL of a http dependency, which is healthy when it responds with a status code below 400
+	URL string `json:"url,omitempty" example:"http://dobby2:4444/health"`
+	// Address of a tcp dependency, which is healthy when a connection can be established
+	Address string `json:"address,omitempty" example:"postgres:5432"`
+	// Probes which fail when the dependency is unhealthy (health|readiness)
+	Probes            []string `json:"probes" example:"readiness"`
+	IntervalInSeconds int      `json:"intervalInSeconds,omitempty" example:"5"`
+	TimeoutInSeconds  int      `json:"timeoutInSeconds,omitempty" example:"2"`
+}
+
+// DependencyCheck model
+type DependencyCheck struct {
+	Name      string    `json:"name" example:"postgres"`
+	Target    string    `json:"target" example:"postgres:5432"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty" example:"dial tcp 10.0.0.1:5432: connect: connection refused"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
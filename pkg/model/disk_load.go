@@ -0,0 +1,16 @@
+package model
+
+// DiskLoad model
+type DiskLoad struct {
+	Status             string `json:"status" example:"holding"`
+	Path               string `json:"path" example:"/data"`
+	FillInMB           int    `json:"fillInMB,omitempty" example:"1024"`
+	FillPercent        int    `json:"fillPercent,omitempty" example:"90"`
+	WriteInMBPerSecond int    `json:"writeInMBPerSecond,omitempty" example:"20"`
+	FilledInMB         int    `json:"filledInMB" example:"1024"`
+	WrittenInMB        int    `json:"writtenInMB" example:"600"`
+	// Error is the last error when filling or writing, e.g. when the disk is full
+	Error string `json:"error,omitempty" example:"write /data/dobby-fill-1234: no space left on device"`
+	// UsedPercent is the usage of the filesystem of the path, when it is known
+	UsedPercent float64 `json:"usedPercent,omitempty" example:"90.2"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Disruption model
+type Disruption struct {
+	ID         int               `json:"id" example:"1"`
+	Type       string            `json:"type" example:"memory"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	StartedAt  time.Time         `json:"startedAt"`
+	Status     string            `json:"status" example:"holding"`
+}
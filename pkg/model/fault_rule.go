@@ -0,0 +1,20 @@
+package model
+
+// FaultRule model
+type FaultRule struct {
+	ID int `json:"id,omitempty" example:"1"`
+	// Path is a glob matching the path of the requests, e.g. /proxy/* - an empty one matches all of them
+	Path   string `json:"path,omitempty" example:"/proxy/*"`
+	Method string `json:"method,omitempty" example:"GET"`
+	// Header is the name of a header the requests should have, with HeaderValue if it is given
+	Header      string `json:"header,omitempty" example:"X-Canary"`
+	HeaderValue string `json:"headerValue,omitempty" example:"true"`
+	// Percentage of the matching requests which fail
+	Percentage float64     `json:"percentage" example:"30"`
+	StatusCode int         `json:"statusCode" example:"503"`
+	Body       interface{} `json:"body,omitempty"`
+	// ConnectionFault breaks the connection instead of responding with the status code,
+	// one of reset, close-after-headers, close-after-bytes, wrong-content-length or malformed-status-line
+	ConnectionFault string `json:"connectionFault,omitempty" example:"reset"`
+	AfterBytes      int    `json:"afterBytes,omitempty" example:"100"`
+}
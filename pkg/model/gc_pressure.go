@@ -0,0 +1,16 @@
+package model
+
+// GCPressure model
+type GCPressure struct {
+	Status                   string `json:"status" example:"running"`
+	ChurnInMBPerSecond       int    `json:"churnInMBPerSecond,omitempty" example:"200"`
+	GCIntervalInMilliseconds int    `json:"gcIntervalInMilliseconds,omitempty" example:"500"`
+	// GOGC and MemoryLimitInMB are left as they are when not given, they are restored once it stops
+	GOGC              *int `json:"gogc,omitempty" example:"20"`
+	MemoryLimitInMB   int  `json:"memoryLimitInMB,omitempty" example:"256"`
+	DurationInSeconds int  `json:"durationInSeconds,omitempty" example:"300"`
+	// NumGC, PauseTotalInMilliseconds and LastPauseInMicroseconds are reported by the go runtime for the whole process
+	NumGC                    uint32  `json:"numGC" example:"1042"`
+	PauseTotalInMilliseconds float64 `json:"pauseTotalInMilliseconds" example:"84.2"`
+	LastPauseInMicroseconds  float64 `json:"lastPauseInMicroseconds" example:"120.5"`
+}
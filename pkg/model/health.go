@@ -2,5 +2,6 @@ package model
 
 // Health model
 type Health struct {
-	Healthy bool `json:"healthy"`
+	Healthy      bool              `json:"healthy"`
+	Dependencies []DependencyCheck `json:"dependencies,omitempty"`
 }
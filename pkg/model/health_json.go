@@ -0,0 +1,30 @@
+package model
+
+// HealthJSON model, the application/health+json rendering of the probes
+// as described in https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check
+type HealthJSON struct {
+	Status    string                   `json:"status" example:"pass"`
+	Version   string                   `json:"version,omitempty" example:"1.0.0"`
+	ReleaseID string                   `json:"releaseId,omitempty" example:"1.0.0-dev"`
+	ServiceID string                   `json:"serviceId,omitempty" example:"dobby"`
+	Output    string                   `json:"output,omitempty" example:"application is not healthy"`
+	Checks    map[string][]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck model
+type HealthCheck struct {
+	ComponentID   string      `json:"componentId,omitempty" example:"postgres"`
+	ComponentType string      `json:"componentType,omitempty" example:"datastore"`
+	ObservedValue interface{} `json:"observedValue,omitempty"`
+	ObservedUnit  string      `json:"observedUnit,omitempty" example:"ms"`
+	Status        string      `json:"status" example:"warn"`
+	Time          string      `json:"time,omitempty" example:"2021-03-16T11:58:02Z"`
+	Output        string      `json:"output,omitempty" example:"replication lag is high"`
+}
+
+// Check model, a named check reported by the probes
+type Check struct {
+	HealthCheck
+	// Probes which report the check (health|readiness), all of them when empty
+	Probes []string `json:"probes,omitempty" example:"readiness"`
+}
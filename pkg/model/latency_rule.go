@@ -0,0 +1,19 @@
+package model
+
+// LatencyRule model
+type LatencyRule struct {
+	ID int `json:"id,omitempty" example:"1"`
+	// Path is a glob matching the path of the requests, e.g. /proxy/* - an empty one matches all of them
+	Path   string `json:"path,omitempty" example:"/call"`
+	Method string `json:"method,omitempty" example:"POST"`
+	// Distribution is one of fixed, uniform, normal or exponential
+	Distribution string `json:"distribution" example:"normal"`
+	// DelayInMilliseconds is the fixed delay, or the mean of the normal and exponential delays
+	DelayInMilliseconds    int `json:"delayInMilliseconds,omitempty" example:"200"`
+	MinDelayInMilliseconds int `json:"minDelayInMilliseconds,omitempty" example:"100"`
+	MaxDelayInMilliseconds int `json:"maxDelayInMilliseconds,omitempty" example:"500"`
+	StdDevInMilliseconds   int `json:"stdDevInMilliseconds,omitempty" example:"50"`
+	// SpikeRate is the probability with which SpikeDelayInMilliseconds is added to the delay
+	SpikeRate                float64 `json:"spikeRate,omitempty" example:"0.01"`
+	SpikeDelayInMilliseconds int     `json:"spikeDelayInMilliseconds,omitempty" example:"5000"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// Leak model
+type Leak struct {
+	Status        string `json:"status" example:"leaking"`
+	Kind          string `json:"kind" example:"sockets"`
+	RatePerSecond int    `json:"ratePerSecond,omitempty" example:"10"`
+	Max           int    `json:"max,omitempty" example:"1000"`
+	Leaked        int    `json:"leaked" example:"420"`
+	// Error is the last error when leaking, e.g. when the process runs out of file descriptors
+	Error        string `json:"error,omitempty" example:"open /dev/null: too many open files"`
+	NumGoroutine int    `json:"numGoroutine" example:"428"`
+}
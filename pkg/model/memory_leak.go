@@ -0,0 +1,18 @@
+package model
+
+// MemoryLeak model
+type MemoryLeak struct {
+	Status            string `json:"status" example:"leaking"`
+	RateInMBPerMinute int    `json:"rateInMBPerMinute" example:"10"`
+	// CeilingInMB is where the leak stops growing, it grows till the process is killed when it is 0
+	CeilingInMB int  `json:"ceilingInMB,omitempty" example:"1024"`
+	Touch       bool `json:"touch"`
+	LeakedInMB  int  `json:"leakedInMB" example:"120"`
+	// ProgressPercent is the percentage of the ceiling leaked so far
+	ProgressPercent float64 `json:"progressPercent,omitempty" example:"11.7"`
+	// CeilingInSeconds is the time left till the ceiling is reached
+	CeilingInSeconds int `json:"ceilingInSeconds,omitempty" example:"5280"`
+	// HeapInUseInMB and SysInMB are reported by the go runtime for the whole process
+	HeapInUseInMB uint64 `json:"heapInUseInMB" example:"124"`
+	SysInMB       uint64 `json:"sysInMB" example:"140"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// MemoryLoad model
+type MemoryLoad struct {
+	Status            string `json:"status" example:"holding"`
+	TargetInMB        int    `json:"targetInMB,omitempty" example:"512"`
+	RampInMBPerSecond int    `json:"rampInMBPerSecond,omitempty" example:"64"`
+	HoldInSeconds     int    `json:"holdInSeconds,omitempty" example:"600"`
+	Touch             bool   `json:"touch"`
+	AllocatedInMB     int    `json:"allocatedInMB" example:"512"`
+	// HeapInUseInMB and SysInMB are reported by the go runtime for the whole process
+	HeapInUseInMB uint64 `json:"heapInUseInMB" example:"516"`
+	SysInMB       uint64 `json:"sysInMB" example:"540"`
+}
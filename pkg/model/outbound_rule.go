@@ -0,0 +1,15 @@
+package model
+
+// OutboundRule model
+type OutboundRule struct {
+	ID int `json:"id,omitempty" example:"1"`
+	// Host is a glob matching the host of the outbound requests, e.g. *.example.com - an empty one matches all of them
+	Host                string `json:"host,omitempty" example:"httpbin.org"`
+	DelayInMilliseconds int    `json:"delayInMilliseconds,omitempty" example:"200"`
+	// Percentage of the matching requests which fail, with StatusCode when it is given or with a connection error
+	Percentage float64 `json:"percentage,omitempty" example:"30"`
+	StatusCode int     `json:"statusCode,omitempty" example:"503"`
+	// Blackhole makes the matching requests hang till TimeoutInSeconds, like a host dropping the packets
+	Blackhole        bool `json:"blackhole,omitempty"`
+	TimeoutInSeconds int  `json:"timeoutInSeconds,omitempty" example:"30"`
+}
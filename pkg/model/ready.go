@@ -2,5 +2,6 @@ package model
 
 // Ready model
 type Ready struct {
-	Ready bool `json:"ready"`
+	Ready        bool              `json:"ready"`
+	Dependencies []DependencyCheck `json:"dependencies,omitempty"`
 }
@@ -0,0 +1,14 @@
+package model
+
+// SlowResponseRule model
+type SlowResponseRule struct {
+	ID int `json:"id,omitempty" example:"1"`
+	// Path is a glob matching the path of the requests, e.g. /proxy/* - an empty one matches all of them
+	Path   string `json:"path,omitempty" example:"/proxy/*"`
+	Method string `json:"method,omitempty" example:"GET"`
+	// Mode is throttle, writing the body at BytesPerSecond,
+	// or slowloris, writing the headers and then a byte of the body every IntervalInSeconds
+	Mode              string `json:"mode" example:"throttle"`
+	BytesPerSecond    int    `json:"bytesPerSecond,omitempty" example:"100"`
+	IntervalInSeconds int    `json:"intervalInSeconds,omitempty" example:"5"`
+}
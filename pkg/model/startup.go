@@ -0,0 +1,6 @@
+package model
+
+// Startup model
+type Startup struct {
+	Started bool `json:"started"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Timer model
+type Timer struct {
+	ID               int       `json:"id" example:"1"`
+	Subject          string    `json:"subject" example:"health"`
+	Action           string    `json:"action" example:"reset health to true"`
+	FiresAt          time.Time `json:"firesAt"`
+	RemainingSeconds float64   `json:"remainingSeconds" example:"42.5"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Transition model
+type Transition struct {
+	Time time.Time `json:"time"`
+	// Subject which changed (health|readiness|startup|proxy|disruption)
+	Subject    string `json:"subject" example:"health"`
+	From       string `json:"from,omitempty" example:"true"`
+	To         string `json:"to,omitempty" example:"false"`
+	Cause      string `json:"cause" example:"PUT /control/health/sick?resetInSeconds=60"`
+	RemoteAddr string `json:"remoteAddr,omitempty" example:"10.0.0.1:53422"`
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net"
+
+	"github.com/thecasualcoder/dobby/pkg/handler"
+)
+
+// hangingListener stops accepting connections while dobby hangs refusing connections,
+// the connections wait in the backlog of the listener till then
+type hangingListener struct {
+	net.Listener
+	h *handler.Handler
+}
+
+// Accept holds the accepted connection while dobby hangs refusing connections,
+// so that even the connection accepted as the hang starts is not served till it resumes
+func (l *hangingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	l.h.WaitToAccept()
+	return conn, nil
+}
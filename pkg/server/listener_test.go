@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHangingListener(t *testing.T) {
+	t.Run("should not accept connections while hanging with refuseConnections", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		admin := gin.New()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		var served int32
+		srv := &http.Server{Handler: router, ConnState: func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&served, 1)
+			}
+		}}
+		h := Bind(router, Config{InitialHealth: true, InitialReadiness: true, InitialStartup: true})
+		BindAdmin(admin, h)
+		go func() {
+			_ = srv.Serve(&hangingListener{Listener: listener, h: h})
+		}()
+		defer func() { _ = srv.Close() }()
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}, Timeout: 300 * time.Millisecond}
+		url := "http://" + listener.Addr().String()
+
+		response, err := client.Get(url + "/health")
+		assert.NoError(t, err)
+		_ = response.Body.Close()
+
+		w := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, "/control/hang?refuseConnections=true", nil)
+		admin.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		_, err = client.Get(url + "/health")
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&served))
+
+		w = httptest.NewRecorder()
+		request, _ = http.NewRequest(http.MethodDelete, "/control/hang", nil)
+		admin.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		client.Timeout = time.Second
+		response, err = client.Get(url + "/health")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		_ = response.Body.Close()
+	})
+}
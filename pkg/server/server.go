@@ -2,6 +2,13 @@ package server
 
 import (
 	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
@@ -11,27 +18,109 @@ import (
 	"github.com/thecasualcoder/dobby/pkg/handler"
 )
 
-// Run the gin server in address and port specified
-func Run(bindAddress, port string, initialHealth, initialReadiness bool) error {
+// Config holds the options with which dobby server is started
+type Config struct {
+	BindAddress      string
+	Port             string
+	InitialHealth    bool
+	InitialReadiness bool
+	InitialStartup   bool
+	// StartupDuration is the time taken to boot, /startup fails till then
+	StartupDuration time.Duration
+	// HealthFailureRate and ReadinessFailureRate are the probabilities with which each probe request fails
+	HealthFailureRate    float64
+	ReadinessFailureRate float64
+	// FailureSeed makes the probabilistic failures reproducible when non zero
+	FailureSeed int64
+	// HealthFormat is the default rendering of the probes (json|health+json)
+	HealthFormat string
+	// HistorySize is the number of state transitions kept in /control/history
+	HistorySize int
+	// DrainPeriod is the time for which dobby stays unready on SIGTERM before shutting down
+	DrainPeriod time.Duration
+	// ShutdownTimeout is the maximum time to wait for in-flight requests while shutting down
+	ShutdownTimeout time.Duration
+	// IgnoreSIGTERM makes dobby ignore SIGTERM, it still shuts down on SIGINT
+	IgnoreSIGTERM bool
+	// ExitDelay is the time for which the process stays alive after shutting down on a signal
+	ExitDelay time.Duration
+	// ExitCode is the code with which the process exits after shutting down on a signal
+	ExitCode int
+	// AdminPort is the port of the admin listener serving the control routes, which keep responding while dobby hangs
+	AdminPort string
+}
+
+// Run the gin server with the given config
+// till it is shut down gracefully on SIGTERM, SIGINT or /control/shutdown
+func Run(config Config) error {
 	r := gin.Default()
 	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", bindAddress, port),
+		Addr:    fmt.Sprintf("%s:%s", config.BindAddress, config.Port),
 		Handler: r,
 	}
 
-	Bind(r, server, initialHealth, initialReadiness)
-	return server.ListenAndServe()
+	h := Bind(r, config)
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	errs := make(chan error, 2)
+	go func() {
+		errs <- server.Serve(&hangingListener{Listener: listener, h: h})
+	}()
+	var adminServer *http.Server
+	if config.AdminPort != "" {
+		admin := gin.Default()
+		BindAdmin(admin, h)
+		adminServer = &http.Server{
+			Addr:    fmt.Sprintf("%s:%s", config.BindAddress, config.AdminPort),
+			Handler: admin,
+		}
+		go func() {
+			errs <- adminServer.ListenAndServe()
+		}()
+	}
+
+	for {
+		select {
+		case err := <-errs:
+			return err
+		case received := <-signals:
+			if received == syscall.SIGTERM && config.IgnoreSIGTERM {
+				log.Printf("received %s, ignoring it", received)
+				continue
+			}
+			err := shutdown(server, h, received.String(), config.DrainPeriod, config.ShutdownTimeout)
+			shutdownAdmin(adminServer, config.ShutdownTimeout)
+			return exitAfter(err, config.ExitDelay, config.ExitCode)
+		case exitCode := <-h.ShutdownRequests():
+			err := shutdown(server, h, "shutdown request", config.DrainPeriod, config.ShutdownTimeout)
+			shutdownAdmin(adminServer, config.ShutdownTimeout)
+			return exitAfter(err, 0, exitCode)
+		}
+	}
 }
 
 // Bind binds all the routes to gin engine
-func Bind(root *gin.Engine, server *http.Server, initialHealth, initialReadiness bool) {
-	h := handler.New(initialHealth, initialReadiness, &http.Client{})
+func Bind(root *gin.Engine, config Config) *handler.Handler {
+	h := handler.New(config.InitialHealth, config.InitialReadiness, &http.Client{})
+	h.SetStartup(config.InitialStartup, config.StartupDuration)
+	h.SetFailureRates(config.HealthFailureRate, config.ReadinessFailureRate, config.FailureSeed)
+	h.SetHealthFormat(config.HealthFormat)
+	h.SetHistorySize(config.HistorySize)
+	root.Use(h.WaitTillResponsive, h.InjectLatency, h.InjectFaults, h.SlowDownResponses)
 	{
 		root.GET("/health", h.Health)
 		root.GET("/readiness", h.Ready)
+		root.GET("/startup", h.Startup)
 		root.GET("/version", h.Version)
 		root.GET("/meta", h.Meta)
 		root.GET("/return/:statusCode", h.HTTPStat)
+		root.GET("/connection/:fault", h.ConnectionFault)
+		root.GET("/slow/:mode", h.SlowResponse)
 		root.POST("/proxy", func(context *gin.Context) {
 			defaultContext := handler.NewDefaultContext(context)
 			h.AddProxy(defaultContext)
@@ -45,19 +134,79 @@ func Bind(root *gin.Engine, server *http.Server, initialHealth, initialReadiness
 			h.Call(defaultContext)
 		})
 	}
-	controlGroup := root.Group("/control")
-	{
-		controlGroup.PUT("/health/perfect", h.MakeHealthPerfect)
-		controlGroup.PUT("/health/sick", h.MakeHealthSick)
-		controlGroup.PUT("/ready/perfect", h.MakeReadyPerfect)
-		controlGroup.PUT("/ready/sick", h.MakeReadySick)
-		controlGroup.PUT("/goturbo/memory", handler.GoTurboMemory)
-		controlGroup.PUT("/goturbo/cpu", handler.GoTurboCPU)
-		controlGroup.PUT("/crash", handler.Crash(server))
-	}
+	bindControl(root.Group("/control"), h)
 	root.NoRoute(func(context *gin.Context) {
 		defaultContext := handler.NewDefaultContext(context)
 		h.ProxyRoute(defaultContext)
 	})
 	root.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	return h
+}
+
+// BindAdmin binds the control routes to the gin engine of the admin listener,
+// they keep responding while dobby hangs
+func BindAdmin(root *gin.Engine, h *handler.Handler) {
+	h.SetAdminListener(true)
+	bindControl(root.Group("/control"), h)
+}
+
+func bindControl(controlGroup *gin.RouterGroup, h *handler.Handler) {
+	controlGroup.PUT("/health/perfect", h.MakeHealthPerfect)
+	controlGroup.PUT("/health/sick", h.MakeHealthSick)
+	controlGroup.PUT("/health/flap", h.MakeHealthFlap)
+	controlGroup.PUT("/health/flaky", h.MakeHealthFlaky)
+	controlGroup.PUT("/health/slow", h.MakeHealthSlow)
+	controlGroup.PUT("/ready/perfect", h.MakeReadyPerfect)
+	controlGroup.PUT("/ready/sick", h.MakeReadySick)
+	controlGroup.PUT("/ready/flap", h.MakeReadyFlap)
+	controlGroup.PUT("/ready/flaky", h.MakeReadyFlaky)
+	controlGroup.PUT("/ready/slow", h.MakeReadySlow)
+	controlGroup.PUT("/startup/perfect", h.MakeStartupPerfect)
+	controlGroup.PUT("/startup/sick", h.MakeStartupSick)
+	controlGroup.POST("/dependencies", h.AddDependency)
+	controlGroup.GET("/dependencies", h.GetDependencies)
+	controlGroup.DELETE("/dependencies/:name", h.DeleteDependency)
+	controlGroup.PUT("/checks/:name", h.SetCheck)
+	controlGroup.GET("/checks", h.GetChecks)
+	controlGroup.DELETE("/checks/:name", h.DeleteCheck)
+	controlGroup.PUT("/goturbo/memory", h.GoTurboMemory)
+	controlGroup.GET("/goturbo/memory", h.GetMemoryLoad)
+	controlGroup.DELETE("/goturbo/memory", h.ReleaseMemory)
+	controlGroup.PUT("/goturbo/cpu", h.GoTurboCPU)
+	controlGroup.GET("/goturbo/cpu", h.GetCPULoad)
+	controlGroup.DELETE("/goturbo/cpu", h.StopCPULoad)
+	controlGroup.PUT("/goturbo/disk", h.GoTurboDisk)
+	controlGroup.GET("/goturbo/disk", h.GetDiskLoad)
+	controlGroup.DELETE("/goturbo/disk", h.CleanDisk)
+	controlGroup.PUT("/goturbo/gc", h.GoTurboGC)
+	controlGroup.GET("/goturbo/gc", h.GetGCPressure)
+	controlGroup.DELETE("/goturbo/gc", h.StopGCPressure)
+	controlGroup.PUT("/leak/memory", h.LeakMemory)
+	controlGroup.GET("/leak/memory", h.GetMemoryLeak)
+	controlGroup.DELETE("/leak/memory", h.FreeMemoryLeak)
+	controlGroup.PUT("/leak/:kind", h.Leak)
+	controlGroup.GET("/leak/:kind", h.GetLeak)
+	controlGroup.DELETE("/leak/:kind", h.FreeLeak)
+	controlGroup.GET("/disruptions", h.GetDisruptions)
+	controlGroup.DELETE("/disruptions/:id", h.StopDisruption)
+	controlGroup.DELETE("/disruptions", h.StopDisruptions)
+	controlGroup.POST("/latency", h.AddLatencyRule)
+	controlGroup.GET("/latency", h.GetLatencyRules)
+	controlGroup.DELETE("/latency/:id", h.DeleteLatencyRule)
+	controlGroup.POST("/faults", h.AddFaultRule)
+	controlGroup.GET("/faults", h.GetFaultRules)
+	controlGroup.DELETE("/faults/:id", h.DeleteFaultRule)
+	controlGroup.POST("/slow", h.AddSlowResponseRule)
+	controlGroup.GET("/slow", h.GetSlowResponseRules)
+	controlGroup.DELETE("/slow/:id", h.DeleteSlowResponseRule)
+	controlGroup.POST("/outbound", h.AddOutboundRule)
+	controlGroup.GET("/outbound", h.GetOutboundRules)
+	controlGroup.DELETE("/outbound/:id", h.DeleteOutboundRule)
+	controlGroup.PUT("/hang", h.Hang)
+	controlGroup.DELETE("/hang", h.Resume)
+	controlGroup.PUT("/crash", handler.Crash)
+	controlGroup.PUT("/shutdown", h.Shutdown)
+	controlGroup.GET("/history", h.GetHistory)
+	controlGroup.GET("/timers", h.GetTimers)
+	controlGroup.DELETE("/timers/:id", h.CancelTimer)
 }
@@ -2,15 +2,22 @@ package server_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/thecasualcoder/dobby/pkg/model"
 	"github.com/thecasualcoder/dobby/pkg/server"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -23,9 +30,7 @@ func TestMain(m *testing.M) {
 func TestHealth(t *testing.T) {
 	t.Run("should return 200", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "GET", "/health", nil)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -36,9 +41,7 @@ func TestHealth(t *testing.T) {
 func TestReadiness(t *testing.T) {
 	t.Run("should return 200", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "GET", "/readiness", nil)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -49,9 +52,7 @@ func TestReadiness(t *testing.T) {
 func TestHealthToggles(t *testing.T) {
 	t.Run("should return 500 when sick and 200 when perfect", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "PUT", "/control/health/sick", nil)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -74,9 +75,7 @@ func TestHealthToggles(t *testing.T) {
 func TestReadinessToggles(t *testing.T) {
 	t.Run("should return 500 when sick and 200 when perfect", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "PUT", "/control/ready/sick", nil)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -96,12 +95,1138 @@ func TestReadinessToggles(t *testing.T) {
 	})
 }
 
+func TestHealthFlap(t *testing.T) {
+	t.Run("should return 400 when period is not given", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/health/flap", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"periodInSeconds should be a positive integer"}`, response.Body.String())
+	})
+
+	t.Run("should alternate health till perfect is called", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/health/flap?periodInSeconds=1&dutyCycle=0.5", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		time.Sleep(600 * time.Millisecond)
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+
+		performRequest(router, "PUT", "/control/health/perfect", nil)
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestReadinessFlaky(t *testing.T) {
+	t.Run("should fail every request when failure rate is 1", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/ready/flaky?failureRate=1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		for i := 0; i < 5; i++ {
+			response = performRequest(router, "GET", "/readiness", nil)
+			assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		}
+
+		performRequest(router, "PUT", "/control/ready/flaky?failureRate=0", nil)
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("should fail every request when configured at startup", func(t *testing.T) {
+		router := gin.Default()
+		config := testConfig()
+		config.HealthFailureRate = 1
+
+		server.Bind(router, config)
+
+		response := performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestHealthSlow(t *testing.T) {
+	t.Run("should delay health response by the given delay", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/health/slow?delay=200", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		start := time.Now()
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+
+		performRequest(router, "PUT", "/control/health/slow", nil)
+		start = time.Now()
+		performRequest(router, "GET", "/health", nil)
+		assert.Less(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("should hang readiness till the client disconnects", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/ready/slow?hang=true", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		request, _ := http.NewRequestWithContext(ctx, "GET", "/readiness", nil)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Empty(t, recorder.Body.String())
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("should return 400 when max delay is less than min delay", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/health/slow?minDelay=200&maxDelay=100", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"maxDelay should not be less than minDelay"}`, response.Body.String())
+	})
+}
+
+func TestDependencies(t *testing.T) {
+	t.Run("should fail readiness when a dependency affecting it fails", func(t *testing.T) {
+		router := gin.Default()
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+		listener, _ := net.Listen("tcp", "127.0.0.1:0")
+		closedAddress := listener.Addr().String()
+		_ = listener.Close()
+
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "POST", "/control/dependencies", bytes.NewBufferString(`{"name": "api", "url": "`+upstream.URL+`", "probes": ["health", "readiness"]}`))
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"healthy":true`)
+		response = performRequest(router, "POST", "/control/dependencies", bytes.NewBufferString(`{"name": "db", "address": "`+closedAddress+`", "probes": ["readiness"]}`))
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"healthy":false`)
+
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		var ready struct {
+			Ready        bool
+			Dependencies []struct {
+				Name    string
+				Healthy bool
+			}
+		}
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &ready))
+		assert.False(t, ready.Ready)
+		assert.Len(t, ready.Dependencies, 2)
+		assert.Equal(t, "api", ready.Dependencies[0].Name)
+		assert.True(t, ready.Dependencies[0].Healthy)
+		assert.Equal(t, "db", ready.Dependencies[1].Name)
+		assert.False(t, ready.Dependencies[1].Healthy)
+
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "DELETE", "/control/dependencies/db", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/dependencies/api", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `{"ready":true}`, response.Body.String())
+	})
+
+	t.Run("should stay healthy while a healthy dependency is being added", func(t *testing.T) {
+		router := gin.Default()
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+
+		server.Bind(router, testConfig())
+
+		added := make(chan struct{})
+		go func() {
+			defer close(added)
+			performRequest(router, "POST", "/control/dependencies", bytes.NewBufferString(`{"name": "api", "url": "`+upstream.URL+`", "probes": ["health"]}`))
+		}()
+		for {
+			select {
+			case <-added:
+				response := performRequest(router, "GET", "/control/dependencies", nil)
+				assert.Contains(t, response.Body.String(), `"healthy":true`)
+				return
+			default:
+			}
+			response := performRequest(router, "GET", "/health", nil)
+			assert.Equal(t, http.StatusOK, response.Code)
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	t.Run("should return 400 when the dependency has no probes", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "POST", "/control/dependencies", bytes.NewBufferString(`{"name": "db", "address": "localhost:5432"}`))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"probes affected by the dependency are required"}`, response.Body.String())
+	})
+}
+
+func TestHealthJSON(t *testing.T) {
+	t.Run("should render health+json when asked in accept header", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/checks/postgres:responseTime", bytes.NewBufferString(`{"status": "warn", "observedValue": 250, "observedUnit": "ms"}`))
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		request, _ := http.NewRequest("GET", "/health", nil)
+		request.Header.Set("Accept", "application/health+json")
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "application/health+json", response.Header().Get("Content-Type"))
+		var health struct {
+			Status    string
+			Version   string
+			ReleaseID string
+			Checks    map[string][]struct {
+				Status        string
+				ObservedValue int
+			}
+		}
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &health))
+		assert.Equal(t, "warn", health.Status)
+		assert.Equal(t, "1.0.0-dev", health.ReleaseID)
+		assert.Equal(t, "warn", health.Checks["postgres:responseTime"][0].Status)
+		assert.Equal(t, 250, health.Checks["postgres:responseTime"][0].ObservedValue)
+	})
+
+	t.Run("should fail the probes reporting a failed check", func(t *testing.T) {
+		router := gin.Default()
+		config := testConfig()
+		config.HealthFormat = "health+json"
+
+		server.Bind(router, config)
+
+		response := performRequest(router, "PUT", "/control/checks/postgres", bytes.NewBufferString(`{"status": "fail", "probes": ["readiness"]}`))
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"fail"`)
+		assert.Contains(t, response.Body.String(), `"output":"application is not ready"`)
+
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Contains(t, response.Body.String(), `"status":"pass"`)
+
+		response = performRequest(router, "DELETE", "/control/checks/postgres", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	t.Run("should record the initial state and the control calls", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		performRequest(router, "PUT", "/control/health/sick?resetInSeconds=60", nil)
+
+		response := performRequest(router, "GET", "/control/history", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		var transitions []struct {
+			Subject string
+			From    string
+			To      string
+			Cause   string
+		}
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &transitions))
+		assert.Len(t, transitions, 4)
+		assert.Equal(t, "health", transitions[0].Subject)
+		assert.Equal(t, "initial state", transitions[0].Cause)
+		assert.Equal(t, "startup", transitions[2].Subject)
+		assert.Equal(t, "health", transitions[3].Subject)
+		assert.Equal(t, "true", transitions[3].From)
+		assert.Equal(t, "false", transitions[3].To)
+		assert.Equal(t, "PUT /control/health/sick?resetInSeconds=60", transitions[3].Cause)
+	})
+
+	t.Run("should record the modes, flaps and checks changing the probes", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		performRequest(router, "PUT", "/control/health/flap?periodInSeconds=1&durationInSeconds=1", nil)
+		performRequest(router, "PUT", "/control/ready/flaky?failureRate=0.5", nil)
+		performRequest(router, "PUT", "/control/checks/postgres", strings.NewReader(`{"status": "fail", "probes": ["readiness"]}`))
+		time.Sleep(1100 * time.Millisecond)
+
+		response := performRequest(router, "GET", "/control/history", nil)
+		var transitions []model.Transition
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &transitions))
+		assert.Len(t, transitions, 8)
+		assert.Equal(t, "flapping every 1s", transitions[3].To)
+		assert.Equal(t, "flaky with failure rate 0.5", transitions[4].To)
+		assert.Equal(t, "readiness", transitions[5].Subject)
+		assert.Equal(t, "false", transitions[5].To)
+		assert.Equal(t, "PUT /control/checks/postgres", transitions[5].Cause)
+		assert.Equal(t, "health", transitions[6].Subject)
+		assert.Equal(t, "false", transitions[6].To)
+		assert.Equal(t, "flap", transitions[6].Cause)
+		assert.Equal(t, "true", transitions[7].To)
+		assert.Equal(t, "flap", transitions[7].Cause)
+	})
+}
+
+func TestTimers(t *testing.T) {
+	t.Run("should not recover health from a superseded timer", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		performRequest(router, "PUT", "/control/health/sick?resetInSeconds=1", nil)
+		performRequest(router, "PUT", "/control/health/perfect", nil)
+		performRequest(router, "PUT", "/control/health/sick", nil)
+
+		response := performRequest(router, "GET", "/control/timers", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `[]`, response.Body.String())
+
+		time.Sleep(time.Second + (10 * time.Millisecond))
+
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+	})
+
+	t.Run("should list and cancel pending timers", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		performRequest(router, "PUT", "/control/ready/sick?resetInSeconds=1", nil)
+
+		response := performRequest(router, "GET", "/control/timers", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		var timers []struct {
+			ID               int
+			Subject          string
+			RemainingSeconds float64
+		}
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &timers))
+		assert.Len(t, timers, 1)
+		assert.Equal(t, "readiness", timers[0].Subject)
+		assert.InDelta(t, 1, timers[0].RemainingSeconds, 0.1)
+
+		response = performRequest(router, "DELETE", "/control/timers/"+strconv.Itoa(timers[0].ID), nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/timers/"+strconv.Itoa(timers[0].ID), nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+
+		time.Sleep(time.Second + (10 * time.Millisecond))
+
+		response = performRequest(router, "GET", "/readiness", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	})
+}
+
+func TestConcurrentControl(t *testing.T) {
+	t.Run("should serve control and proxy endpoints in parallel", func(t *testing.T) {
+		var proxied int32
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&proxied, 1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+		router := gin.New()
+		server.Bind(router, testConfig())
+		proxy := `{"path": "/v1/version", "method": "GET", "proxy": {"url": "` + upstream.URL + `", "method": "GET"}}`
+
+		requests := []struct {
+			method string
+			path   string
+			body   string
+		}{
+			{"PUT", "/control/health/sick?resetInSeconds=1", ""},
+			{"PUT", "/control/health/perfect", ""},
+			{"PUT", "/control/health/flap?periodInSeconds=1", ""},
+			{"PUT", "/control/health/flaky?failureRate=0.5", ""},
+			{"PUT", "/control/ready/sick", ""},
+			{"PUT", "/control/ready/perfect", ""},
+			{"PUT", "/control/ready/slow?delay=1", ""},
+			{"PUT", "/control/startup/sick", ""},
+			{"PUT", "/control/startup/perfect", ""},
+			{"GET", "/health", ""},
+			{"GET", "/readiness", ""},
+			{"GET", "/startup", ""},
+			{"GET", "/version", ""},
+			{"GET", "/control/history", ""},
+			{"GET", "/control/timers", ""},
+			{"POST", "/proxy", proxy},
+			{"GET", "/v1/version", ""},
+			{"DELETE", "/proxy", `{"path": "/v1/version", "method": "GET"}`},
+			{"GET", "/v1/version", ""},
+		}
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					request := requests[(worker+i)%len(requests)]
+					performRequest(router, request.method, request.path, strings.NewReader(request.body))
+				}
+			}(worker)
+		}
+		wg.Wait()
+		assert.Greater(t, atomic.LoadInt32(&proxied), int32(0))
+
+		response := performRequest(router, "PUT", "/control/health/perfect", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+}
+
+func TestCrash(t *testing.T) {
+	t.Run("should not accept an unknown crash mode", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/crash?mode=gracefully", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Contains(t, response.Body.String(), "mode should be one of concurrent-map-write, exit, fatal, nil, panic, sigabrt, sigkill, sigsegv")
+	})
+
+	t.Run("should not accept invalid exit code or delay", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/crash?mode=exit&exitCode=-1", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		response = performRequest(router, "PUT", "/control/crash?mode=panic&delayInSeconds=soon", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestMemoryLoad(t *testing.T) {
+	t.Run("should allocate, report and release memory", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/memory?targetInMB=2&touch=true", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		time.Sleep(100 * time.Millisecond)
+		response = performRequest(router, "GET", "/control/goturbo/memory", nil)
+		var load model.MemoryLoad
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &load))
+		assert.Equal(t, "holding", load.Status)
+		assert.Equal(t, 2, load.AllocatedInMB)
+
+		response = performRequest(router, "DELETE", "/control/goturbo/memory", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/goturbo/memory", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should not accept negative target", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/memory?targetInMB=-1", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"targetInMB should be a non negative integer"}`, response.Body.String())
+	})
+}
+
+func TestCPULoad(t *testing.T) {
+	t.Run("should run, report and stop cpu load", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/cpu?cores=1&utilisationPercent=20&durationInSeconds=60", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "GET", "/control/goturbo/cpu", nil)
+		var load model.CPULoad
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &load))
+		assert.Equal(t, "running", load.Status)
+		assert.Equal(t, 20, load.UtilisationPercent)
+
+		response = performRequest(router, "DELETE", "/control/goturbo/cpu", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/goturbo/cpu", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should not accept utilisation above 100", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/cpu?utilisationPercent=120", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"utilisationPercent should be between 1 and 100"}`, response.Body.String())
+	})
+
+	t.Run("should not accept more cores than the cpus", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/cpu?cores="+strconv.Itoa(runtime.NumCPU()+1), nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"cores should be between 1 and `+strconv.Itoa(runtime.NumCPU())+`"}`, response.Body.String())
+	})
+}
+
+func TestDisruptions(t *testing.T) {
+	t.Run("should list and stop the started disruptions", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		performRequest(router, "PUT", "/control/goturbo/memory?targetInMB=1", nil)
+		performRequest(router, "PUT", "/control/goturbo/cpu?utilisationPercent=10", nil)
+
+		response := performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		var disruptions []model.Disruption
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &disruptions))
+		assert.Len(t, disruptions, 2)
+		assert.Equal(t, "memory", disruptions[0].Type)
+		assert.Equal(t, map[string]string{"targetInMB": "1"}, disruptions[0].Parameters)
+		assert.Equal(t, "cpu", disruptions[1].Type)
+		assert.Equal(t, "running", disruptions[1].Status)
+
+		response = performRequest(router, "DELETE", "/control/disruptions/2", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/control/goturbo/cpu", nil)
+		assert.Contains(t, response.Body.String(), `"status":"idle"`)
+		response = performRequest(router, "DELETE", "/control/disruptions/2", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+
+		response = performRequest(router, "DELETE", "/control/disruptions", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/control/goturbo/memory", nil)
+		assert.Contains(t, response.Body.String(), `"status":"idle"`)
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Equal(t, `[]`, response.Body.String())
+	})
+
+	t.Run("should keep only the current disruption of a kind", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		for i := 0; i < 3; i++ {
+			response := performRequest(router, "PUT", "/control/goturbo/memory?targetInMB=1", nil)
+			assert.Equal(t, http.StatusOK, response.Code)
+		}
+		response := performRequest(router, "GET", "/control/disruptions", nil)
+		var disruptions []model.Disruption
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &disruptions))
+		assert.Len(t, disruptions, 1)
+		assert.Equal(t, 3, disruptions[0].ID)
+
+		response = performRequest(router, "DELETE", "/control/goturbo/memory", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Equal(t, `[]`, response.Body.String())
+		response = performRequest(router, "DELETE", "/control/disruptions/1", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should not stop a disruption which ended by itself", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/cpu?utilisationPercent=10&durationInSeconds=1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		time.Sleep(1200 * time.Millisecond)
+
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Contains(t, response.Body.String(), `"status":"stopped"`)
+		response = performRequest(router, "DELETE", "/control/disruptions/1", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		assert.Equal(t, `{"error":"disruption 1 is not running"}`, response.Body.String())
+		response = performRequest(router, "GET", "/control/history", nil)
+		assert.NotContains(t, response.Body.String(), "disruption 1 stopped")
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Equal(t, `[]`, response.Body.String())
+	})
+
+	t.Run("should forget a rule deleted through its own route", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		body := strings.NewReader(`{"path": "/version", "distribution": "fixed", "delayInMilliseconds": 10}`)
+		response := performRequest(router, "POST", "/control/latency", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		response = performRequest(router, "DELETE", "/control/latency/1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Equal(t, `[]`, response.Body.String())
+	})
+}
+
+func TestLeak(t *testing.T) {
+	t.Run("should leak, report and free sockets", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/leak/sockets?ratePerSecond=100&max=3", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		time.Sleep(200 * time.Millisecond)
+		response = performRequest(router, "GET", "/control/leak/sockets", nil)
+		var leak model.Leak
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &leak))
+		assert.Equal(t, "holding", leak.Status)
+		assert.Equal(t, 3, leak.Leaked)
+
+		response = performRequest(router, "DELETE", "/control/leak/sockets", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/leak/sockets", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should leak, report and free memory", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/leak/memory?rateInMBPerMinute=6000&ceilingInMB=2", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		time.Sleep(100 * time.Millisecond)
+
+		response = performRequest(router, "GET", "/control/leak/memory", nil)
+		var leak model.MemoryLeak
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &leak))
+		assert.Equal(t, "holding", leak.Status)
+		assert.Equal(t, 2, leak.LeakedInMB)
+		assert.Equal(t, float64(100), leak.ProgressPercent)
+
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		assert.Contains(t, response.Body.String(), `"type":"memory leak"`)
+
+		response = performRequest(router, "DELETE", "/control/leak/memory", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/leak/memory", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+		response = performRequest(router, "PUT", "/control/leak/memory?rateInMBPerMinute=0", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		response = performRequest(router, "PUT", "/control/leak/memory?rateInMBPerMinute=100000000000", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"rateInMBPerMinute should be between 1 and 60000"}`, response.Body.String())
+	})
+
+	t.Run("should free the memory leak when its disruption is stopped", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/leak/memory?ceilingInMB=1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "PUT", "/control/leak/memory?ceilingInMB=2", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/control/disruptions", nil)
+		var disruptions []model.Disruption
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &disruptions))
+		assert.Len(t, disruptions, 1)
+
+		response = performRequest(router, "DELETE", "/control/disruptions/"+strconv.Itoa(disruptions[0].ID), nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "GET", "/control/leak/memory", nil)
+		assert.Contains(t, response.Body.String(), `"status":"idle"`)
+		response = performRequest(router, "DELETE", "/control/leak/memory", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should not accept a rate above the limit", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/leak/goroutines?ratePerSecond=2000000000", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"ratePerSecond should be between 1 and 1000"}`, response.Body.String())
+	})
+
+	t.Run("should not accept an unknown kind", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/leak/threads", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"kind should be one of files, goroutines, sockets"}`, response.Body.String())
+	})
+}
+
+func TestDiskLoad(t *testing.T) {
+	t.Run("should fill, report and clean the disk", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/disk?path="+t.TempDir()+"&fillInMB=1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		time.Sleep(200 * time.Millisecond)
+		response = performRequest(router, "GET", "/control/goturbo/disk", nil)
+		var load model.DiskLoad
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &load))
+		assert.Equal(t, "holding", load.Status)
+		assert.Equal(t, 1, load.FilledInMB)
+
+		response = performRequest(router, "DELETE", "/control/goturbo/disk", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/goturbo/disk", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should not accept both size and percentage to fill", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/disk?fillInMB=1&fillPercent=90", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"only one of fillInMB and fillPercent is allowed"}`, response.Body.String())
+	})
+
+	t.Run("should not accept a write rate above the limit", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/disk?writeInMBPerSecond=100000", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"writeInMBPerSecond should be at most 10000"}`, response.Body.String())
+	})
+}
+
+func TestHang(t *testing.T) {
+	t.Run("should block requests till the duration", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/hang?durationInSeconds=1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		start := time.Now()
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.True(t, time.Since(start) > 900*time.Millisecond)
+	})
+
+	t.Run("should require a duration without an admin listener", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/hang", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"durationInSeconds is required when there is no admin listener"}`, response.Body.String())
+	})
+
+	t.Run("should resume through the admin listener", func(t *testing.T) {
+		router := gin.Default()
+		admin := gin.Default()
+
+		h := server.Bind(router, testConfig())
+		server.BindAdmin(admin, h)
+
+		response := performRequest(router, "PUT", "/control/hang", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		responded := make(chan int)
+		go func() {
+			responded <- performRequest(router, "GET", "/health", nil).Code
+		}()
+		select {
+		case <-responded:
+			t.Fatal("responded while hanging")
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		response = performRequest(admin, "DELETE", "/control/hang", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, http.StatusOK, <-responded)
+		response = performRequest(admin, "DELETE", "/control/hang", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+
+		response = performRequest(admin, "GET", "/control/history", nil)
+		var transitions []model.Transition
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &transitions))
+		var hangs []string
+		for _, transition := range transitions {
+			if transition.Subject == "disruption" {
+				hangs = append(hangs, transition.To)
+			}
+		}
+		assert.Equal(t, []string{"hanging", "resumed"}, hangs)
+	})
+}
+
+func TestGCPressure(t *testing.T) {
+	t.Run("should run, report and stop gc pressure", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/gc?gcIntervalInMilliseconds=50&durationInSeconds=60", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		time.Sleep(200 * time.Millisecond)
+		response = performRequest(router, "GET", "/control/goturbo/gc", nil)
+		var pressure model.GCPressure
+		assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &pressure))
+		assert.Equal(t, "running", pressure.Status)
+		assert.NotZero(t, pressure.NumGC)
+
+		response = performRequest(router, "DELETE", "/control/goturbo/gc", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "DELETE", "/control/goturbo/gc", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+	})
+
+	t.Run("should require one of the parameters", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "PUT", "/control/goturbo/gc", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		response = performRequest(router, "PUT", "/control/goturbo/gc?gogc=-2", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestLatency(t *testing.T) {
+	t.Run("should delay the requests matching the rule till it is deleted", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		body := strings.NewReader(`{"path": "/version", "method": "GET", "distribution": "fixed", "delayInMilliseconds": 200}`)
+		response := performRequest(router, "POST", "/control/latency", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.Contains(t, response.Body.String(), `"id":1`)
+
+		start := time.Now()
+		response = performRequest(router, "GET", "/version", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.True(t, time.Since(start) >= 200*time.Millisecond)
+
+		start = time.Now()
+		performRequest(router, "GET", "/meta", nil)
+		assert.True(t, time.Since(start) < 200*time.Millisecond)
+
+		response = performRequest(router, "GET", "/control/latency", nil)
+		assert.Equal(t, `[{"id":1,"path":"/version","method":"GET","distribution":"fixed","delayInMilliseconds":200}]`, response.Body.String())
+
+		response = performRequest(router, "DELETE", "/control/latency/1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		start = time.Now()
+		performRequest(router, "GET", "/version", nil)
+		assert.True(t, time.Since(start) < 200*time.Millisecond)
+	})
+
+	t.Run("should not accept an unknown distribution", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "POST", "/control/latency", strings.NewReader(`{"distribution": "poisson"}`))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"distribution should be one of fixed, uniform, normal, exponential"}`, response.Body.String())
+	})
+}
+
+func TestFaults(t *testing.T) {
+	t.Run("should fail the requests matching the rule till it is deleted", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		body := strings.NewReader(`{"path": "/version", "header": "X-Canary", "percentage": 100, "statusCode": 503, "body": {"error": "unavailable"}}`)
+		response := performRequest(router, "POST", "/control/faults", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+
+		request, _ := http.NewRequest("GET", "/version", nil)
+		request.Header.Set("X-Canary", "true")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, `{"error":"unavailable"}`, w.Body.String())
+
+		response = performRequest(router, "GET", "/version", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "DELETE", "/control/faults/1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should fail the proxy routes", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		body := strings.NewReader(`{"path": "/time/*", "percentage": 100, "statusCode": 502}`)
+		response := performRequest(router, "POST", "/control/faults", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+
+		response = performRequest(router, "GET", "/time/kolkata", nil)
+		assert.Equal(t, http.StatusBadGateway, response.Code)
+		response = performRequest(router, "GET", "/control/faults", nil)
+		assert.Equal(t, `[{"id":1,"path":"/time/*","percentage":100,"statusCode":502}]`, response.Body.String())
+	})
+}
+
+func TestConnectionFaults(t *testing.T) {
+	router := gin.Default()
+	testServer := httptest.NewServer(router)
+	defer testServer.Close()
+	server.Bind(router, testConfig())
+
+	t.Run("should break the connection with the fault", func(t *testing.T) {
+		for _, fault := range []string{"reset", "close-after-headers", "close-after-bytes?afterBytes=10", "malformed-status-line"} {
+			response, err := http.Get(testServer.URL + "/connection/" + fault)
+			if err == nil {
+				_, err = io.ReadAll(response.Body)
+				_ = response.Body.Close()
+			}
+			assert.Error(t, err, fault)
+		}
+	})
+
+	t.Run("should send a longer body than the content length", func(t *testing.T) {
+		conn, err := net.Dial("tcp", testServer.Listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte("GET /connection/wrong-content-length?afterBytes=10 HTTP/1.1\r\nHost: dobby\r\n\r\n"))
+		assert.NoError(t, err)
+		raw, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+
+		parts := strings.SplitN(string(raw), "\r\n\r\n", 2)
+		assert.Contains(t, parts[0], "Content-Length: 10")
+		assert.Greater(t, len(parts[1]), 10)
+	})
+
+	t.Run("should write the whole body when it spans several blocks", func(t *testing.T) {
+		conn, err := net.Dial("tcp", testServer.Listener.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte("GET /connection/wrong-content-length?afterBytes=10000 HTTP/1.1\r\nHost: dobby\r\n\r\n"))
+		assert.NoError(t, err)
+		raw, err := io.ReadAll(conn)
+		assert.NoError(t, err)
+
+		parts := strings.SplitN(string(raw), "\r\n\r\n", 2)
+		assert.Contains(t, parts[0], "Content-Length: 10000")
+		assert.Equal(t, strings.Repeat("x", 11024), parts[1])
+	})
+
+	t.Run("should not accept unknown faults", func(t *testing.T) {
+		response := performRequest(router, "GET", "/connection/explode", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		response = performRequest(router, "GET", "/connection/close-after-bytes?afterBytes=many", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("should not accept afterBytes above the limit", func(t *testing.T) {
+		response := performRequest(router, "GET", "/connection/wrong-content-length?afterBytes=9223372036854775807", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"afterBytes should be between 0 and 1048576"}`, response.Body.String())
+	})
+
+	t.Run("should break the connection of the requests matching the fault rule", func(t *testing.T) {
+		body := strings.NewReader(`{"path": "/meta", "percentage": 100, "connectionFault": "reset"}`)
+		response := performRequest(router, "POST", "/control/faults", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+
+		_, err := http.Get(testServer.URL + "/meta")
+		assert.Error(t, err)
+		versionResponse, err := http.Get(testServer.URL + "/version")
+		assert.NoError(t, err)
+		_ = versionResponse.Body.Close()
+		assert.Equal(t, http.StatusOK, versionResponse.StatusCode)
+	})
+}
+
+func TestSlowResponses(t *testing.T) {
+	router := gin.Default()
+	testServer := httptest.NewServer(router)
+	defer testServer.Close()
+	server.Bind(router, testConfig())
+
+	t.Run("should throttle the body to the rate", func(t *testing.T) {
+		start := time.Now()
+		response := performRequest(router, "GET", "/slow/throttle?bytesPerSecond=200&sizeInBytes=100", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, strings.Repeat("x", 100), response.Body.String())
+		assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("should send the headers and then drip the body", func(t *testing.T) {
+		start := time.Now()
+		response, err := http.Get(testServer.URL + "/slow/slowloris?intervalInSeconds=1&sizeInBytes=2")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, "2", response.Header.Get("Content-Length"))
+
+		body, err := io.ReadAll(response.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "xx", string(body))
+		assert.GreaterOrEqual(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("should not accept invalid modes", func(t *testing.T) {
+		response := performRequest(router, "GET", "/slow/crawl", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		response = performRequest(router, "GET", "/slow/throttle?bytesPerSecond=fast", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		response = performRequest(router, "GET", "/slow/throttle?bytesPerSecond=100&sizeInBytes=9223372036854775807", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+		assert.Equal(t, `{"error":"sizeInBytes should be at most 10485760"}`, response.Body.String())
+	})
+
+	t.Run("should stream a body spanning several blocks", func(t *testing.T) {
+		response := performRequest(router, "GET", "/slow/throttle?bytesPerSecond=100000000000&sizeInBytes=10000", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "10000", response.Header().Get("Content-Length"))
+		assert.Equal(t, "text/plain", response.Header().Get("Content-Type"))
+		assert.Equal(t, strings.Repeat("x", 10000), response.Body.String())
+	})
+
+	t.Run("should slow down the responses of the requests matching the rule till it is deleted", func(t *testing.T) {
+		body := strings.NewReader(`{"path": "/version", "mode": "throttle", "bytesPerSecond": 20}`)
+		response := performRequest(router, "POST", "/control/slow", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		response = performRequest(router, "GET", "/control/slow", nil)
+		assert.Equal(t, `[{"id":1,"path":"/version","mode":"throttle","bytesPerSecond":20}]`, response.Body.String())
+
+		start := time.Now()
+		response = performRequest(router, "GET", "/version", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.GreaterOrEqual(t, time.Since(start), time.Duration(response.Body.Len())*time.Second/20)
+
+		response = performRequest(router, "DELETE", "/control/slow/1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		start = time.Now()
+		performRequest(router, "GET", "/version", nil)
+		assert.Less(t, time.Since(start), 100*time.Millisecond)
+	})
+}
+
+func TestOutbound(t *testing.T) {
+	t.Run("should degrade the calls to the host till the rule is deleted", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		body := strings.NewReader(`{"host": "127.0.0.1", "percentage": 100, "statusCode": 503}`)
+		response := performRequest(router, "POST", "/control/outbound", body)
+		assert.Equal(t, http.StatusCreated, response.Code)
+		response = performRequest(router, "GET", "/control/outbound", nil)
+		assert.Equal(t, `[{"id":1,"host":"127.0.0.1","percentage":100,"statusCode":503}]`, response.Body.String())
+
+		call := `{"url": "` + upstream.URL + `", "method": "GET"}`
+		response = performRequest(router, "POST", "/call", strings.NewReader(call))
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+
+		response = performRequest(router, "DELETE", "/control/outbound/1", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		response = performRequest(router, "POST", "/call", strings.NewReader(call))
+		assert.Equal(t, http.StatusOK, response.Code)
+	})
+
+	t.Run("should not accept a rule which does not degrade the calls", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "POST", "/control/outbound", strings.NewReader(`{"host": "127.0.0.1"}`))
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
+
+func TestStartup(t *testing.T) {
+	t.Run("should return 200", func(t *testing.T) {
+		router := gin.Default()
+		server.Bind(router, testConfig())
+
+		response := performRequest(router, "GET", "/startup", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `{"started":true}`, response.Body.String())
+	})
+
+	t.Run("should return 503 till the startup duration is over", func(t *testing.T) {
+		router := gin.Default()
+		config := testConfig()
+		config.StartupDuration = 500 * time.Millisecond
+
+		server.Bind(router, config)
+
+		response := performRequest(router, "GET", "/startup", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		assert.Equal(t, `{"started":false}`, response.Body.String())
+
+		response = performRequest(router, "GET", "/health", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		time.Sleep(config.StartupDuration + (10 * time.Millisecond))
+
+		response = performRequest(router, "GET", "/startup", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `{"started":true}`, response.Body.String())
+	})
+}
+
+func TestStartupToggles(t *testing.T) {
+	t.Run("should return 503 when sick and 200 when perfect", func(t *testing.T) {
+		router := gin.Default()
+		config := testConfig()
+		config.InitialStartup = false
+
+		server.Bind(router, config)
+
+		response := performRequest(router, "GET", "/startup", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		assert.Equal(t, `{"started":false}`, response.Body.String())
+
+		response = performRequest(router, "PUT", "/control/startup/perfect", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `{"status":"success"}`, response.Body.String())
+
+		response = performRequest(router, "GET", "/startup", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, `{"started":true}`, response.Body.String())
+
+		response = performRequest(router, "PUT", "/control/startup/sick", nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		response = performRequest(router, "GET", "/startup", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		assert.Equal(t, `{"started":false}`, response.Body.String())
+	})
+}
+
 func TestVersion(t *testing.T) {
 	t.Run("should return 200 with version", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "GET", "/version", nil)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -115,9 +1240,7 @@ func TestVersion(t *testing.T) {
 		defer func() {
 			_ = os.Setenv("VERSION", existingVersion)
 		}()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "GET", "/version", nil)
 		assert.Equal(t, http.StatusOK, response.Code)
@@ -126,9 +1249,7 @@ func TestVersion(t *testing.T) {
 
 	t.Run("should return 500 if service is unhealthy", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		// make health sick
 		performRequest(router, "PUT", "/control/health/sick", nil)
@@ -140,9 +1261,7 @@ func TestVersion(t *testing.T) {
 
 	t.Run("should mark service as not healthy till n seconds", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		// make service not ready
 		resetInSeconds := 1
@@ -161,9 +1280,7 @@ func TestVersion(t *testing.T) {
 
 	t.Run("should return 503 if service is not ready", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		// make service not ready
 		performRequest(router, "PUT", "/control/ready/sick", nil)
@@ -175,9 +1292,7 @@ func TestVersion(t *testing.T) {
 
 	t.Run("should mark service as not ready till n seconds", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		// make service not ready
 		resetInSeconds := 1
@@ -198,9 +1313,7 @@ func TestVersion(t *testing.T) {
 func TestCall(t *testing.T) {
 	t.Run("should make request to another url and return the response", func(t *testing.T) {
 		router := gin.Default()
-		srv := httptest.NewServer(router).Config
-
-		server.Bind(router, srv, true, true)
+		server.Bind(router, testConfig())
 
 		response := performRequest(router, "POST", "/call", bytes.NewBufferString(`
 {
@@ -213,6 +1326,10 @@ func TestCall(t *testing.T) {
 	})
 }
 
+func testConfig() server.Config {
+	return server.Config{InitialHealth: true, InitialReadiness: true, InitialStartup: true}
+}
+
 func performRequest(r http.Handler, method, path string, body io.Reader) *httptest.ResponseRecorder {
 	req, _ := http.NewRequest(method, path, body)
 	responseWriter := httptest.NewRecorder()
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thecasualcoder/dobby/pkg/handler"
+)
+
+// exit can be replaced while testing
+var exit = os.Exit
+
+// shutdown drains the traffic by marking dobby unready for drainPeriod,
+// and then gracefully shuts down the server waiting at most timeout for in-flight requests
+func shutdown(server *http.Server, h *handler.Handler, reason string, drainPeriod, timeout time.Duration) error {
+	log.Printf("received %s, marking readiness as false", reason)
+	h.MarkUnready(fmt.Sprintf("received %s", reason))
+
+	log.Printf("draining for %s", drainPeriod)
+	time.Sleep(drainPeriod)
+
+	log.Printf("shutting down the server, waiting at most %s for in-flight requests", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error when shutting down the server: %s", err)
+	}
+	log.Printf("server is shut down")
+	return nil
+}
+
+// shutdownAdmin shuts down the admin listener once the server is shut down,
+// a nil admin server is not started and is left as it is
+func shutdownAdmin(adminServer *http.Server, timeout time.Duration) {
+	if adminServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := adminServer.Shutdown(ctx); err != nil {
+		log.Printf("error when shutting down the admin listener: %s", err)
+	}
+}
+
+// exitAfter keeps the process alive for delay once it is shut down,
+// and then exits with the given code
+func exitAfter(err error, delay time.Duration, exitCode int) error {
+	if delay > 0 {
+		log.Printf("taking %s to exit", delay)
+		time.Sleep(delay)
+	}
+	if err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		log.Printf("exiting with code %d", exitCode)
+		exit(exitCode)
+	}
+	return nil
+}
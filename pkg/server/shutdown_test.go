@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("should stay unready while draining and then stop serving", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		srv := &http.Server{Handler: router}
+		h := Bind(router, Config{InitialHealth: true, InitialReadiness: true, InitialStartup: true})
+		go func() {
+			_ = srv.Serve(listener)
+		}()
+		url := "http://" + listener.Addr().String()
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+		done := make(chan error)
+		go func() {
+			done <- shutdown(srv, h, syscall.SIGTERM.String(), 300*time.Millisecond, time.Second)
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		response, err := client.Get(url + "/readiness")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+		_ = response.Body.Close()
+		response, err = client.Get(url + "/health")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		_ = response.Body.Close()
+
+		assert.NoError(t, <-done)
+		_, err = client.Get(url + "/health")
+		assert.Error(t, err)
+	})
+}
+
+func TestExitAfter(t *testing.T) {
+	exited := -1
+	exit = func(code int) { exited = code }
+	defer func() { exit = os.Exit }()
+
+	t.Run("should exit with the given code after the delay", func(t *testing.T) {
+		exited = -1
+		start := time.Now()
+		assert.NoError(t, exitAfter(nil, 100*time.Millisecond, 3))
+		assert.Equal(t, 3, exited)
+		assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	})
+	t.Run("should not exit when the code is 0", func(t *testing.T) {
+		exited = -1
+		assert.NoError(t, exitAfter(nil, 0, 0))
+		assert.Equal(t, -1, exited)
+	})
+	t.Run("should return the shutdown error instead of exiting", func(t *testing.T) {
+		exited = -1
+		assert.Error(t, exitAfter(errors.New("deadline exceeded"), 0, 3))
+		assert.Equal(t, -1, exited)
+	})
+}
+
+func TestShutdownRequest(t *testing.T) {
+	t.Run("should request shutdown with the exit code after the delay", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		h := Bind(router, Config{InitialHealth: true, InitialReadiness: true, InitialStartup: true})
+
+		w := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, "/control/shutdown?exitCode=3&delayInSeconds=1", nil)
+		router.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		select {
+		case code := <-h.ShutdownRequests():
+			t.Fatalf("shutdown requested with %d before the delay", code)
+		case <-time.After(500 * time.Millisecond):
+		}
+		select {
+		case code := <-h.ShutdownRequests():
+			assert.Equal(t, 3, code)
+		case <-time.After(time.Second):
+			t.Fatal("shutdown is not requested")
+		}
+	})
+	t.Run("should not accept invalid exit code", func(t *testing.T) {
+		router := gin.New()
+		Bind(router, Config{})
+
+		w := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodPut, "/control/shutdown?exitCode=300", nil)
+		router.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRunShutdown(t *testing.T) {
+	t.Run("should shut down the admin listener along with the server", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		freePort := func() string {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			defer listener.Close()
+			return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+		}
+		config := Config{BindAddress: "127.0.0.1", Port: freePort(), AdminPort: freePort(), InitialHealth: true,
+			InitialReadiness: true, InitialStartup: true, ShutdownTimeout: time.Second}
+		done := make(chan error, 1)
+		go func() {
+			done <- Run(config)
+		}()
+		adminURL := "http://127.0.0.1:" + config.AdminPort
+		assert.Eventually(t, func() bool {
+			response, err := http.Get(adminURL + "/control/history")
+			if err == nil {
+				_ = response.Body.Close()
+			}
+			return err == nil
+		}, 2*time.Second, 10*time.Millisecond)
+
+		request, _ := http.NewRequest(http.MethodPut, adminURL+"/control/shutdown", nil)
+		response, err := http.DefaultClient.Do(request)
+		assert.NoError(t, err)
+		_ = response.Body.Close()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("server is not shut down")
+		}
+		_, err = net.DialTimeout("tcp", "127.0.0.1:"+config.AdminPort, 100*time.Millisecond)
+		assert.Error(t, err)
+	})
+}